@@ -0,0 +1,65 @@
+package herschel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// columnName returns A1 notation name of column at index. e.g. 0 -> A, 26 -> AA
+func columnName(col int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name
+}
+
+// quoteSheetTitle returns sheet title quoted for A1 notation.
+func quoteSheetTitle(sheetTitle string) string {
+	return "'" + strings.ReplaceAll(sheetTitle, "'", "''") + "'"
+}
+
+// cellRange returns A1 notation of range in sheet.
+func cellRange(sheetTitle string, rowStart, colStart, numRows, numCols int) string {
+	start := fmt.Sprintf("%s%d", columnName(colStart), rowStart+1)
+	end := fmt.Sprintf("%s%d", columnName(colStart+numCols-1), rowStart+numRows)
+	if start == end {
+		return quoteSheetTitle(sheetTitle) + "!" + start
+	}
+	return quoteSheetTitle(sheetTitle) + "!" + start + ":" + end
+}
+
+// rowsRange returns A1 notation of whole rows in sheet. e.g. 'Sheet1'!3:5
+func rowsRange(sheetTitle string, rowStart, numRows int) string {
+	return fmt.Sprintf("%s!%d:%d", quoteSheetTitle(sheetTitle), rowStart+1, rowStart+numRows)
+}
+
+// Limits of rows and columns of a worksheet.
+const (
+	maxSheetRows = 1048576
+	maxSheetCols = 16384
+)
+
+// parseCellRef parses A1 notation of a cell such as "B3" into indices of row and column.
+// Cells beyond maxSheetRows or maxSheetCols are invalid.
+func parseCellRef(ref string) (int, int, bool) {
+	i := 0
+	col := 0
+	for i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z' {
+		col = col*26 + int(ref[i]-'A'+1)
+		if col > maxSheetCols {
+			return 0, 0, false
+		}
+		i++
+	}
+	if i == 0 || i == len(ref) {
+		return 0, 0, false
+	}
+	row, err := strconv.Atoi(ref[i:])
+	if err != nil || row < 1 || row > maxSheetRows || ref[i] == '+' || ref[i] == '-' {
+		return 0, 0, false
+	}
+	return row - 1, col - 1, true
+}
@@ -0,0 +1,80 @@
+package herschel
+
+import "testing"
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		col  int
+		want string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{25, "Z"},
+		{26, "AA"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+	for _, tt := range tests {
+		if got := columnName(tt.col); got != tt.want {
+			t.Errorf("columnName(%d) = %s, want %s", tt.col, got, tt.want)
+		}
+	}
+}
+
+func TestCellRange(t *testing.T) {
+	tests := []struct {
+		name                                 string
+		sheetTitle                           string
+		rowStart, colStart, numRows, numCols int
+		want                                 string
+	}{
+		{"SingleCell", "Sheet1", 0, 0, 1, 1, "'Sheet1'!A1"},
+		{"Range", "Sheet1", 2, 1, 1, 3, "'Sheet1'!B3:D3"},
+		{"QuotedTitle", "Bob's", 0, 0, 2, 2, "'Bob''s'!A1:B2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cellRange(tt.sheetTitle, tt.rowStart, tt.colStart, tt.numRows, tt.numCols); got != tt.want {
+				t.Errorf("cellRange() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRowsRange(t *testing.T) {
+	if got := rowsRange("Sheet1", 0, 100); got != "'Sheet1'!1:100" {
+		t.Errorf("rowsRange() = %s, want 'Sheet1'!1:100", got)
+	}
+	if got := rowsRange("Sheet1", 100, 50); got != "'Sheet1'!101:150" {
+		t.Errorf("rowsRange() = %s, want 'Sheet1'!101:150", got)
+	}
+}
+
+func TestParseCellRef(t *testing.T) {
+	tests := []struct {
+		ref      string
+		row, col int
+		ok       bool
+	}{
+		{"A1", 0, 0, true},
+		{"B3", 2, 1, true},
+		{"AA10", 9, 26, true},
+		{"A", 0, 0, false},
+		{"1", 0, 0, false},
+		{"A0", 0, 0, false},
+		{"A-1", 0, 0, false},
+		{"XFD1048576", 1048575, 16383, true},
+		{"XFE1", 0, 0, false},
+		{"A1048577", 0, 0, false},
+		{"A2000000000", 0, 0, false},
+		{"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAA1", 0, 0, false},
+	}
+	for _, tt := range tests {
+		row, col, ok := parseCellRef(tt.ref)
+		if ok != tt.ok || (ok && (row != tt.row || col != tt.col)) {
+			t.Errorf("parseCellRef(%s) = %d, %d, %v", tt.ref, row, col, ok)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 // Client provides methods to manipulate spreadsheets.
 type Client struct {
 	service *sheets.Service
+	cache   *metadataCache
 }
 
 // NewClient returns a new instance
@@ -23,38 +24,95 @@ func NewClient(option option.ClientOption) (*Client, error) {
 		return nil, errors.Wrap(err, "failed to create service with client")
 	}
 
-	return &Client{service: service}, nil
+	return &Client{service: service, cache: newMetadataCache()}, nil
 }
 
 /*
  * Low-level Spreadsheet api calls
  */
-func (c Client) updateCellValues(spreadsheetID string, sheetName string, values [][]interface{}) error {
+func (c Client) spreadsheets() (*sheets.SpreadsheetsService, error) {
 	if c.service == nil {
-		return errors.New("service not initiallized")
+		return nil, ErrServiceNotInitialized
 	}
-	if _, err := c.service.Spreadsheets.Values.Update(spreadsheetID, sheetName, &sheets.ValueRange{
+	return c.service.Spreadsheets, nil
+}
+
+// updateCellValues writes values to valueRange, which is sheetTitle itself or a range in sheet with sheetTitle.
+func (c Client) updateCellValues(spreadsheetID string, sheetTitle string, valueRange string, values [][]interface{}) error {
+	s, err := c.spreadsheets()
+	if err != nil {
+		return err
+	}
+	if _, err := s.Values.Update(spreadsheetID, valueRange, &sheets.ValueRange{
 		MajorDimension: "ROWS",
-		Values:         values,
+		Values:         userEnteredValues(values),
 	}).ValueInputOption("USER_ENTERED").Do(); err != nil {
-		return err
+		return c.valuesAPIError(spreadsheetID, sheetTitle, err)
 	}
 
 	return nil
 }
 
-func (c Client) batchUpdate(spreadsheetID string, requests []*sheets.Request) error {
-	if c.service == nil {
-		return errors.New("service not initiallized")
+// batchUpdateValues writes values to ranges in sheet with sheetTitle.
+func (c Client) batchUpdateValues(spreadsheetID string, sheetTitle string, data []*sheets.ValueRange) error {
+	s, err := c.spreadsheets()
+	if err != nil {
+		return err
 	}
-	if len(requests) == 0 {
+	if len(data) == 0 {
 		return nil
 	}
+	for _, d := range data {
+		d.Values = userEnteredValues(d.Values)
+	}
 
-	if _, err := c.service.Spreadsheets.BatchUpdate(spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
-		Requests: requests,
+	if _, err := s.Values.BatchUpdate(spreadsheetID, &sheets.BatchUpdateValuesRequest{
+		ValueInputOption: "USER_ENTERED",
+		Data:             data,
 	}).Do(); err != nil {
+		return c.valuesAPIError(spreadsheetID, sheetTitle, err)
+	}
+	return nil
+}
+
+func (c Client) clearValues(spreadsheetID string, ranges []string) error {
+	s, err := c.spreadsheets()
+	if err != nil {
 		return err
 	}
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	if _, err := s.Values.BatchClear(spreadsheetID, &sheets.BatchClearValuesRequest{
+		Ranges: ranges,
+	}).Do(); err != nil {
+		return wrapAPIError(err)
+	}
 	return nil
 }
+
+func (c Client) batchUpdate(spreadsheetID string, requests []*sheets.Request) error {
+	_, err := c.batchUpdateWithReplies(spreadsheetID, requests)
+	return err
+}
+
+func (c Client) batchUpdateWithReplies(spreadsheetID string, requests []*sheets.Request) ([]*sheets.Response, error) {
+	s, err := c.spreadsheets()
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	resp, err := s.BatchUpdate(spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}).Do()
+	if err != nil {
+		// Sheet IDs in requests may be stale when sheets are recreated outside of the client.
+		c.cache.invalidate(spreadsheetID)
+		return nil, wrapAPIError(err)
+	}
+	return resp.Replies, nil
+}
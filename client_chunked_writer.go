@@ -0,0 +1,183 @@
+package herschel
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// DefaultChunkRows is the number of rows written per request when ChunkedWriteOptions.ChunkRows is not set.
+const DefaultChunkRows = 1000
+
+// ChunkedWriteOptions configures WriteTableInChunks.
+type ChunkedWriteOptions struct {
+	// ChunkRows is the number of rows written per request. DefaultChunkRows is used when zero.
+	ChunkRows int
+	// Concurrency is the maximum number of chunks written at the same time. Chunks are written sequentially when zero or one.
+	Concurrency int
+	// Progress is called each time a chunk is written. It is never called concurrently.
+	Progress func(WriteProgress)
+}
+
+// WriteProgress reports progress of WriteTableInChunks.
+type WriteProgress struct {
+	WrittenChunks int
+	TotalChunks   int
+	WrittenRows   int
+	TotalRows     int
+}
+
+// WriteTableInChunks writes table to sheet in blocks of rows, so that each request stays within payload size limits.
+// Values and formats of a block are written together. Grid of the sheet is expanded when the table does not fit.
+func (client Client) WriteTableInChunks(spreadsheetID string, sheetTitle string, table *Table, opts ChunkedWriteOptions) error {
+	chunkRows := opts.ChunkRows
+	if chunkRows == 0 {
+		chunkRows = DefaultChunkRows
+	}
+	if chunkRows < 0 {
+		return errors.Errorf("chunk rows should be positive, got: %d", chunkRows)
+	}
+
+	properties, err := client.sheetPropertiesByTitle(spreadsheetID, sheetTitle)
+	if err != nil {
+		return err
+	}
+	if rows, cols, expand := requiredGridLimits(properties, table); expand {
+		if err := client.UpdateSheetGridLimits(spreadsheetID, sheetTitle, rows, cols); err != nil {
+			return err
+		}
+	}
+
+	chunks := rowChunks(table.rows, chunkRows)
+	progress := WriteProgress{TotalChunks: len(chunks), TotalRows: table.rows}
+	var mu sync.Mutex
+
+	err = runChunks(len(chunks), opts.Concurrency, func(i int) error {
+		rowStart, rowEnd := chunks[i][0], chunks[i][1]
+		if err := client.writeRows(spreadsheetID, sheetTitle, table, rowStart, rowEnd); err != nil {
+			return err
+		}
+		if err := client.batchUpdate(spreadsheetID, rowFormatRequests(properties.SheetId, table, rowStart, rowEnd)); err != nil {
+			return errors.Wrapf(err, "failed to write formats of rows from %d to %d", rowStart, rowEnd)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		progress.WrittenChunks++
+		progress.WrittenRows += rowEnd - rowStart
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	requests := append(frozenCountRequests(properties.SheetId, table), columnWidthRequests(properties.SheetId, table)...)
+	if err := client.batchUpdate(spreadsheetID, requests); err != nil {
+		return err
+	}
+	return client.protectHeaderRows(spreadsheetID, properties.SheetId, table)
+}
+
+func (client Client) sheetPropertiesByTitle(spreadsheetID string, sheetTitle string) (*sheets.SheetProperties, error) {
+	properties, err := getSheetProperties(client, spreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range properties {
+		if p.Title == sheetTitle {
+			return p, nil
+		}
+	}
+	return nil, sheetNotFoundError(sheetTitle)
+}
+
+func (client Client) writeRows(spreadsheetID string, sheetTitle string, table *Table, rowStart int, rowEnd int) error {
+	if table.cols == 0 {
+		return nil
+	}
+	values := make([][]interface{}, 0, rowEnd-rowStart)
+	for row := rowStart; row < rowEnd; row++ {
+		values = append(values, table.GetValuesAtRow(row))
+	}
+	if err := client.updateCellValues(spreadsheetID, sheetTitle, cellRange(sheetTitle, rowStart, 0, len(values), table.cols), values); err != nil {
+		return errors.Wrapf(err, "failed to write values of rows from %d to %d", rowStart, rowEnd)
+	}
+	return nil
+}
+
+// requiredGridLimits returns grid size of sheet needed to hold table, and whether the sheet should be expanded.
+func requiredGridLimits(properties *sheets.SheetProperties, table *Table) (int, int, bool) {
+	rows, cols := 0, 0
+	if g := properties.GridProperties; g != nil {
+		rows, cols = int(g.RowCount), int(g.ColumnCount)
+	}
+	expand := false
+	if table.rows > rows {
+		rows = table.rows
+		expand = true
+	}
+	if table.cols > cols {
+		cols = table.cols
+		expand = true
+	}
+	return rows, cols, expand
+}
+
+// rowChunks splits rows into blocks of chunkRows rows. Each block is a pair of start (inclusive) and end (exclusive) rows.
+func rowChunks(rows int, chunkRows int) [][2]int {
+	chunks := [][2]int{}
+	for start := 0; start < rows; start += chunkRows {
+		end := start + chunkRows
+		if end > rows {
+			end = rows
+		}
+		chunks = append(chunks, [2]int{start, end})
+	}
+	return chunks
+}
+
+// runChunks calls write for chunks from 0 to n-1 with at most concurrency calls at a time, and returns the first error.
+// Chunks not started yet are skipped after an error.
+func runChunks(n int, concurrency int, write func(i int) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		if failed() {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := write(i); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,123 @@
+package herschel
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestWriteTableInChunks(t *testing.T) {
+	spreadsheetID := createNewSpreadsheet(t)
+	c := newTestClient(t)
+
+	sheetTitle := t.Name()
+	if err := c.RecreateSheet(spreadsheetID, sheetTitle); err != nil {
+		t.Fatal(err)
+	}
+
+	// Larger than the default grid of 1000 rows.
+	table := NewTable(1200, 2)
+	for row := 0; row < table.GetRows(); row++ {
+		table.PutValuesAtRow(row, "row", row)
+	}
+
+	progress := []WriteProgress{}
+	if err := c.WriteTableInChunks(spreadsheetID, sheetTitle, table, ChunkedWriteOptions{
+		ChunkRows:   500,
+		Concurrency: 2,
+		Progress: func(p WriteProgress) {
+			progress = append(progress, p)
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(progress) != 3 || progress[2].WrittenRows != 1200 || progress[2].TotalChunks != 3 {
+		t.Errorf("Unexpected progress: %v", progress)
+	}
+
+	written, err := c.ReadTable(spreadsheetID, sheetTitle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written.GetRows() != 1200 || written.GetStringValue(1199, 1) != "1199" {
+		t.Errorf("Unexpected table written: %d rows", written.GetRows())
+	}
+}
+
+func TestRowChunks(t *testing.T) {
+	if got := rowChunks(5, 2); !reflect.DeepEqual(got, [][2]int{{0, 2}, {2, 4}, {4, 5}}) {
+		t.Errorf("Unexpected chunks: %v", got)
+	}
+	if got := rowChunks(4, 2); !reflect.DeepEqual(got, [][2]int{{0, 2}, {2, 4}}) {
+		t.Errorf("Unexpected chunks: %v", got)
+	}
+	if got := rowChunks(0, 2); len(got) != 0 {
+		t.Errorf("Empty table should have no chunks: %v", got)
+	}
+}
+
+func TestRequiredGridLimits(t *testing.T) {
+	properties := &sheets.SheetProperties{GridProperties: &sheets.GridProperties{RowCount: 1000, ColumnCount: 26}}
+
+	if _, _, expand := requiredGridLimits(properties, NewTable(1000, 26)); expand {
+		t.Error("Grid should not be expanded for table which fits.")
+	}
+	if rows, cols, expand := requiredGridLimits(properties, NewTable(1200, 3)); !expand || rows != 1200 || cols != 26 {
+		t.Errorf("Unexpected grid limits: %d x %d", rows, cols)
+	}
+}
+
+func TestRunChunks(t *testing.T) {
+	t.Run("BoundedConcurrency", func(t *testing.T) {
+		var mu sync.Mutex
+		running, maxRunning := 0, 0
+		written := make([]bool, 10)
+
+		err := runChunks(10, 3, func(i int) error {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			written[i] = true
+			mu.Unlock()
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if maxRunning > 3 {
+			t.Errorf("At most 3 chunks should be written at a time, got: %d", maxRunning)
+		}
+		for i, w := range written {
+			if !w {
+				t.Errorf("Chunk %d is not written", i)
+			}
+		}
+	})
+
+	t.Run("StopsAtError", func(t *testing.T) {
+		written := 0
+		err := runChunks(10, 1, func(i int) error {
+			written++
+			if i == 2 {
+				return ErrQuotaExceeded
+			}
+			return nil
+		})
+		if !errors.Is(err, ErrQuotaExceeded) {
+			t.Errorf("ErrQuotaExceeded expected, got: %v", err)
+		}
+		if written != 3 {
+			t.Errorf("Chunks after the error should be skipped, %d written", written)
+		}
+	})
+}
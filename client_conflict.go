@@ -0,0 +1,125 @@
+package herschel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Fingerprint is a hash of values in sheet, used to detect concurrent changes.
+//
+// Checks with fingerprints are best-effort. The spreadsheet api can not make a write conditional,
+// so the fingerprint is read again just before the write, and a change made between the check and the write is overwritten.
+// They catch changes made while a job is processing what it read, not two jobs writing at the same moment.
+type Fingerprint string
+
+// Fingerprint returns fingerprint of current values in sheet.
+func (client Client) Fingerprint(spreadsheetID string, sheetTitle string) (Fingerprint, error) {
+	values, err := client.readUserEnteredValues(spreadsheetID, sheetTitle)
+	if err != nil {
+		return "", err
+	}
+	return fingerprintOf(values), nil
+}
+
+// ReadTableWithFingerprint returns a table with values read from sheet and fingerprint of the values.
+// Values are read as the strings a user would enter, with formulas and unformatted numbers,
+// so that writing the table back does not replace formulas with their results.
+func (client Client) ReadTableWithFingerprint(spreadsheetID string, sheetTitle string) (*Table, Fingerprint, error) {
+	values, err := client.readUserEnteredValues(spreadsheetID, sheetTitle)
+	if err != nil {
+		return nil, "", err
+	}
+	return tableFromValues(values), fingerprintOf(values), nil
+}
+
+// WriteIfUnchanged writes values to sheet only if fingerprint of sheet still matches. Otherwise returns ErrConflict.
+// The check is best-effort: it is a separate read before the write, so changes made in between are not detected.
+func (client Client) WriteIfUnchanged(spreadsheetID string, sheetTitle string, values [][]interface{}, fingerprint Fingerprint) error {
+	if err := client.checkFingerprint(spreadsheetID, sheetTitle, fingerprint); err != nil {
+		return err
+	}
+	return client.Write(spreadsheetID, sheetTitle, values)
+}
+
+// WriteTableIfUnchanged writes table to sheet only if fingerprint of sheet still matches. Otherwise returns ErrConflict.
+// The check is best-effort like WriteIfUnchanged.
+func (client Client) WriteTableIfUnchanged(spreadsheetID string, sheetTitle string, table *Table, fingerprint Fingerprint) error {
+	if err := client.checkFingerprint(spreadsheetID, sheetTitle, fingerprint); err != nil {
+		return err
+	}
+	return client.WriteTable(spreadsheetID, sheetTitle, table)
+}
+
+// ModifyTable reads table from sheet, modifies it with modify and writes it back.
+// When the sheet is changed concurrently, it retries up to maxAttempts times in total.
+// Values left outside of the table are cleared when modify shrinks it.
+// Conflicts are detected with fingerprints, so two jobs writing at the same moment can still overwrite each other.
+func (client Client) ModifyTable(spreadsheetID string, sheetTitle string, maxAttempts int, modify func(t *Table) error) error {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var table *Table
+		var fingerprint Fingerprint
+		table, fingerprint, err = client.ReadTableWithFingerprint(spreadsheetID, sheetTitle)
+		if err != nil {
+			return err
+		}
+		rows, cols := table.GetRows(), table.GetCols()
+		if err := modify(table); err != nil {
+			return err
+		}
+
+		err = client.WriteTableIfUnchanged(spreadsheetID, sheetTitle, table, fingerprint)
+		if err == nil {
+			return client.clearValues(spreadsheetID, shrunkRanges(sheetTitle, rows, cols, table.GetRows(), table.GetCols()))
+		}
+		if !errors.Is(err, ErrConflict) {
+			return err
+		}
+	}
+	return errors.Wrapf(err, "gave up after %d attempts", maxAttempts)
+}
+
+func (client Client) checkFingerprint(spreadsheetID string, sheetTitle string, fingerprint Fingerprint) error {
+	current, err := client.Fingerprint(spreadsheetID, sheetTitle)
+	if err != nil {
+		return err
+	}
+	if current != fingerprint {
+		return errors.Wrapf(ErrConflict, "fingerprint of %s changed from %s to %s", sheetTitle, fingerprint, current)
+	}
+	return nil
+}
+
+// shrunkRanges returns A1 ranges of cells which were in a table of rows x cols but are not in a table of newRows x newCols.
+func shrunkRanges(sheetTitle string, rows int, cols int, newRows int, newCols int) []string {
+	ranges := []string{}
+	if newCols < cols && newRows > 0 && rows > 0 {
+		numRows := rows
+		if newRows < numRows {
+			numRows = newRows
+		}
+		ranges = append(ranges, cellRange(sheetTitle, 0, newCols, numRows, cols-newCols))
+	}
+	if newRows < rows {
+		ranges = append(ranges, rowsRange(sheetTitle, newRows, rows-newRows))
+	}
+	return ranges
+}
+
+func fingerprintOf(values [][]interface{}) Fingerprint {
+	h := sha256.New()
+	for _, row := range values {
+		for _, v := range row {
+			s := userEnteredString(v)
+			fmt.Fprintf(h, "%d:%s", len(s), s)
+		}
+		h.Write([]byte{'\n'})
+	}
+	return Fingerprint(hex.EncodeToString(h.Sum(nil)))
+}
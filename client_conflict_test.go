@@ -0,0 +1,116 @@
+package herschel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestConflictDetection(t *testing.T) {
+	spreadsheetID := createNewSpreadsheet(t)
+	c := newTestClient(t)
+
+	sheetTitle := t.Name()
+	if err := c.RecreateSheet(spreadsheetID, sheetTitle); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Write(spreadsheetID, sheetTitle, [][]interface{}{{"count", 0}}); err != nil {
+		t.Fatal(err)
+	}
+
+	table, fingerprint, err := c.ReadTableWithFingerprint(spreadsheetID, sheetTitle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another job updates the sheet.
+	if err := c.Write(spreadsheetID, sheetTitle, [][]interface{}{{"count", 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	table.PutValue(0, 1, 2)
+	if err := c.WriteTableIfUnchanged(spreadsheetID, sheetTitle, table, fingerprint); !errors.Is(err, ErrConflict) {
+		t.Errorf("ErrConflict expected, got: %v", err)
+	}
+
+	if err := c.ModifyTable(spreadsheetID, sheetTitle, 3, func(t *Table) error {
+		t.PutValue(0, 1, t.GetIntValue(0, 1)+1)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("KeepFormulasAndFormats", func(t *testing.T) {
+		if err := c.Write(spreadsheetID, sheetTitle, [][]interface{}{{1234, "12%", "=A1*2"}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.ModifyTable(spreadsheetID, sheetTitle, 1, func(t *Table) error {
+			if got := t.GetStringValue(0, 2); got != "=A1*2" {
+				return errors.Errorf("formula expected, got: %s", got)
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		table, _, err := c.ReadTableWithFingerprint(spreadsheetID, sheetTitle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(table.GetValuesAtRow(0), []interface{}{"1234", "0.12", "=A1*2"}) {
+			t.Errorf("Values should be kept, got: %v", table.GetValuesAtRow(0))
+		}
+	})
+
+	if err := c.Write(spreadsheetID, sheetTitle, [][]interface{}{{"a", "b"}, {"c", "d"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ModifyTable(spreadsheetID, sheetTitle, 1, func(t *Table) error {
+		return t.RemoveColAtIndex(1)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	values, err := c.Read(spreadsheetID, sheetTitle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values, [][]interface{}{{"a"}, {"c"}}) {
+		t.Errorf("Values outside of shrunk table should be cleared, got: %v", values)
+	}
+}
+
+func TestShrunkRanges(t *testing.T) {
+	tests := []struct {
+		rows, cols, newRows, newCols int
+		want                         []string
+	}{
+		{3, 3, 3, 3, []string{}},
+		{3, 3, 4, 4, []string{}},
+		{3, 3, 1, 3, []string{"'S'!2:3"}},
+		{3, 3, 3, 1, []string{"'S'!B1:C3"}},
+		{3, 3, 2, 2, []string{"'S'!C1:C2", "'S'!3:3"}},
+		{3, 3, 0, 0, []string{"'S'!1:3"}},
+	}
+	for _, tt := range tests {
+		if got := shrunkRanges("S", tt.rows, tt.cols, tt.newRows, tt.newCols); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Unexpected ranges for %d x %d -> %d x %d: %v, want %v", tt.rows, tt.cols, tt.newRows, tt.newCols, got, tt.want)
+		}
+	}
+}
+
+func TestFingerprintOf(t *testing.T) {
+	a := fingerprintOf([][]interface{}{{"a", "b"}, {"c"}})
+
+	if b := fingerprintOf([][]interface{}{{"a", "b"}, {"c"}}); a != b {
+		t.Errorf("Same values should have same fingerprint: %s, %s", a, b)
+	}
+	if b := fingerprintOf([][]interface{}{{"a"}, {"b", "c"}}); a == b {
+		t.Error("Different shape should have different fingerprint.")
+	}
+	if b := fingerprintOf([][]interface{}{{"ab"}, {"c"}}); a == b {
+		t.Error("Concatenated values should have different fingerprint.")
+	}
+	if b := fingerprintOf([][]interface{}{{"a", "b"}, {"d"}}); a == b {
+		t.Error("Different values should have different fingerprint.")
+	}
+}
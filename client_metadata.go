@@ -0,0 +1,225 @@
+package herschel
+
+import (
+	"github.com/pkg/errors"
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// MetadataLocation is a location where developer metadata is attached.
+type MetadataLocation struct {
+	spreadsheet bool
+	sheetTitle  string
+	dimension   string
+	start       int
+	end         int
+}
+
+// SpreadsheetLocation returns a location of the whole spreadsheet.
+func SpreadsheetLocation() MetadataLocation {
+	return MetadataLocation{spreadsheet: true}
+}
+
+// SheetLocation returns a location of sheet with title.
+func SheetLocation(sheetTitle string) MetadataLocation {
+	return MetadataLocation{sheetTitle: sheetTitle}
+}
+
+// RowsLocation returns a location of rows from start (inclusive) to end (exclusive) in sheet.
+func RowsLocation(sheetTitle string, start int, end int) MetadataLocation {
+	return MetadataLocation{sheetTitle: sheetTitle, dimension: "ROWS", start: start, end: end}
+}
+
+// ColumnsLocation returns a location of columns from start (inclusive) to end (exclusive) in sheet.
+func ColumnsLocation(sheetTitle string, start int, end int) MetadataLocation {
+	return MetadataLocation{sheetTitle: sheetTitle, dimension: "COLUMNS", start: start, end: end}
+}
+
+// DeveloperMetadata represents developer metadata attached to spreadsheet, sheet, rows or columns.
+type DeveloperMetadata struct {
+	ID    int64
+	Key   string
+	Value string
+	// LocationType is one of SPREADSHEET, SHEET, ROW or COLUMN.
+	LocationType string
+	// SheetID is the ID of sheet for metadata on sheet, rows or columns.
+	SheetID int64
+	// StartIndex and EndIndex are the range of rows or columns.
+	StartIndex int
+	EndIndex   int
+}
+
+// CreateDeveloperMetadata attaches developer metadata with key and value to location, and returns ID of the metadata.
+func (client Client) CreateDeveloperMetadata(spreadsheetID string, location MetadataLocation, key string, value string) (int64, error) {
+	l, err := client.developerMetadataLocation(spreadsheetID, location)
+	if err != nil {
+		return 0, err
+	}
+
+	replies, err := client.batchUpdateWithReplies(spreadsheetID, []*sheets.Request{
+		{
+			CreateDeveloperMetadata: &sheets.CreateDeveloperMetadataRequest{
+				DeveloperMetadata: &sheets.DeveloperMetadata{
+					Location:      l,
+					MetadataKey:   key,
+					MetadataValue: value,
+					Visibility:    "DOCUMENT",
+				},
+			},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(replies) == 0 || replies[0].CreateDeveloperMetadata == nil || replies[0].CreateDeveloperMetadata.DeveloperMetadata == nil {
+		return 0, errors.New("no developer metadata in response")
+	}
+	return replies[0].CreateDeveloperMetadata.DeveloperMetadata.MetadataId, nil
+}
+
+// SearchDeveloperMetadata returns developer metadata with key. Empty value matches any value.
+func (client Client) SearchDeveloperMetadata(spreadsheetID string, key string, value string) ([]DeveloperMetadata, error) {
+	s, err := client.spreadsheets()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.DeveloperMetadata.Search(spreadsheetID, &sheets.SearchDeveloperMetadataRequest{
+		DataFilters: []*sheets.DataFilter{
+			{
+				DeveloperMetadataLookup: &sheets.DeveloperMetadataLookup{
+					MetadataKey:   key,
+					MetadataValue: value,
+				},
+			},
+		},
+	}).Do()
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	found := []DeveloperMetadata{}
+	for _, m := range resp.MatchedDeveloperMetadata {
+		if m.DeveloperMetadata != nil {
+			found = append(found, newDeveloperMetadata(m.DeveloperMetadata))
+		}
+	}
+	return found, nil
+}
+
+// DeleteDeveloperMetadata deletes developer metadata with ID.
+func (client Client) DeleteDeveloperMetadata(spreadsheetID string, metadataID int64) error {
+	return client.deleteDeveloperMetadata(spreadsheetID, &sheets.DeveloperMetadataLookup{
+		MetadataId:      metadataID,
+		ForceSendFields: []string{"MetadataId"},
+	})
+}
+
+// DeleteDeveloperMetadataByKey deletes all developer metadata with key. Empty key is an error.
+func (client Client) DeleteDeveloperMetadataByKey(spreadsheetID string, key string) error {
+	if len(key) == 0 {
+		return errors.New("empty developer metadata key")
+	}
+	return client.deleteDeveloperMetadata(spreadsheetID, &sheets.DeveloperMetadataLookup{MetadataKey: key})
+}
+
+// SheetTitleByMetadata returns current title of the sheet tagged with developer metadata key and value.
+func (client Client) SheetTitleByMetadata(spreadsheetID string, key string, value string) (string, error) {
+	found, err := client.SearchDeveloperMetadata(spreadsheetID, key, value)
+	if err != nil {
+		return "", err
+	}
+
+	if len(found) == 0 {
+		return "", errors.Wrapf(ErrSheetNotFound, "sheet with metadata %s=%s", key, value)
+	}
+	properties, err := getSheetProperties(client, spreadsheetID)
+	if err != nil {
+		return "", err
+	}
+	for _, m := range found {
+		if m.LocationType != "SHEET" {
+			continue
+		}
+		for _, p := range properties {
+			if p.SheetId == m.SheetID {
+				return p.Title, nil
+			}
+		}
+	}
+	return "", errors.Wrapf(ErrSheetNotFound, "sheet with metadata %s=%s", key, value)
+}
+
+// ReadTableByMetadata returns a table read from the sheet tagged with developer metadata key and value.
+func (client Client) ReadTableByMetadata(spreadsheetID string, key string, value string) (*Table, error) {
+	sheetTitle, err := client.SheetTitleByMetadata(spreadsheetID, key, value)
+	if err != nil {
+		return nil, err
+	}
+	return client.ReadTable(spreadsheetID, sheetTitle)
+}
+
+// WriteTableByMetadata writes table to the sheet tagged with developer metadata key and value.
+func (client Client) WriteTableByMetadata(spreadsheetID string, key string, value string, table *Table) error {
+	sheetTitle, err := client.SheetTitleByMetadata(spreadsheetID, key, value)
+	if err != nil {
+		return err
+	}
+	return client.WriteTable(spreadsheetID, sheetTitle, table)
+}
+
+func (client Client) deleteDeveloperMetadata(spreadsheetID string, lookup *sheets.DeveloperMetadataLookup) error {
+	return client.batchUpdate(spreadsheetID, []*sheets.Request{
+		{
+			DeleteDeveloperMetadata: &sheets.DeleteDeveloperMetadataRequest{
+				DataFilter: &sheets.DataFilter{DeveloperMetadataLookup: lookup},
+			},
+		},
+	})
+}
+
+func (client Client) developerMetadataLocation(spreadsheetID string, location MetadataLocation) (*sheets.DeveloperMetadataLocation, error) {
+	if location.spreadsheet {
+		return &sheets.DeveloperMetadataLocation{Spreadsheet: true}, nil
+	}
+
+	sheetID, exists, err := getSheetID(client, spreadsheetID, location.sheetTitle)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sheetNotFoundError(location.sheetTitle)
+	}
+	return toDeveloperMetadataLocation(sheetID, location), nil
+}
+
+func toDeveloperMetadataLocation(sheetID int64, location MetadataLocation) *sheets.DeveloperMetadataLocation {
+	if len(location.dimension) == 0 {
+		return &sheets.DeveloperMetadataLocation{SheetId: sheetID, ForceSendFields: []string{"SheetId"}}
+	}
+	return &sheets.DeveloperMetadataLocation{
+		DimensionRange: &sheets.DimensionRange{
+			SheetId:         sheetID,
+			Dimension:       location.dimension,
+			StartIndex:      int64(location.start),
+			EndIndex:        int64(location.end),
+			ForceSendFields: []string{"SheetId", "StartIndex"},
+		},
+	}
+}
+
+func newDeveloperMetadata(m *sheets.DeveloperMetadata) DeveloperMetadata {
+	d := DeveloperMetadata{
+		ID:    m.MetadataId,
+		Key:   m.MetadataKey,
+		Value: m.MetadataValue,
+	}
+	if l := m.Location; l != nil {
+		d.LocationType = l.LocationType
+		d.SheetID = l.SheetId
+		if r := l.DimensionRange; r != nil {
+			d.SheetID = r.SheetId
+			d.StartIndex = int(r.StartIndex)
+			d.EndIndex = int(r.EndIndex)
+		}
+	}
+	return d
+}
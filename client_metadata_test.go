@@ -0,0 +1,110 @@
+package herschel
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestDeveloperMetadata(t *testing.T) {
+	spreadsheetID := createNewSpreadsheet(t)
+	c := newTestClient(t)
+
+	sheetTitle := t.Name()
+	if err := c.RecreateSheet(spreadsheetID, sheetTitle); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CreateDeveloperMetadata(spreadsheetID, SheetLocation(sheetTitle), "job", "daily-report"); err != nil {
+		t.Fatal(err)
+	}
+	rowMetadataID, err := c.CreateDeveloperMetadata(spreadsheetID, RowsLocation(sheetTitle, 0, 1), "header", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := sheetTitle + "Renamed"
+	if err := c.RenameSheet(spreadsheetID, sheetTitle, renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("FindSheetByMetadata", func(t *testing.T) {
+		title, err := c.SheetTitleByMetadata(spreadsheetID, "job", "daily-report")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if title != renamed {
+			t.Errorf("%s expected, got: %s", renamed, title)
+		}
+	})
+
+	t.Run("WriteAndReadByMetadata", func(t *testing.T) {
+		table := NewTable(1, 1)
+		table.PutValue(0, 0, "Hello")
+		if err := c.WriteTableByMetadata(spreadsheetID, "job", "daily-report", table); err != nil {
+			t.Fatal(err)
+		}
+		read, err := c.ReadTableByMetadata(spreadsheetID, "job", "daily-report")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read.GetValue(0, 0) != "Hello" {
+			t.Errorf("Hello expected, got: %v", read.GetValue(0, 0))
+		}
+	})
+
+	t.Run("SearchAndDelete", func(t *testing.T) {
+		found, err := c.SearchDeveloperMetadata(spreadsheetID, "header", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != 1 || found[0].ID != rowMetadataID || found[0].LocationType != "ROW" {
+			t.Fatalf("Unexpected metadata: %+v", found)
+		}
+		if err := c.DeleteDeveloperMetadata(spreadsheetID, rowMetadataID); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.DeleteDeveloperMetadataByKey(spreadsheetID, "job"); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestToDeveloperMetadataLocation(t *testing.T) {
+	b, err := toDeveloperMetadataLocation(0, SheetLocation("Sheet1")).MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"sheetId":0}` {
+		t.Errorf("Sheet ID 0 should be sent, got: %s", b)
+	}
+
+	l := toDeveloperMetadataLocation(10, ColumnsLocation("Sheet1", 0, 2))
+	if r := l.DimensionRange; r == nil || r.SheetId != 10 || r.Dimension != "COLUMNS" || r.StartIndex != 0 || r.EndIndex != 2 {
+		t.Errorf("Unexpected dimension range: %+v", l.DimensionRange)
+	}
+}
+
+func TestNewDeveloperMetadata(t *testing.T) {
+	d := newDeveloperMetadata(&sheets.DeveloperMetadata{
+		MetadataId:    1,
+		MetadataKey:   "key",
+		MetadataValue: "value",
+		Location: &sheets.DeveloperMetadataLocation{
+			LocationType:   "ROW",
+			DimensionRange: &sheets.DimensionRange{SheetId: 10, StartIndex: 1, EndIndex: 3},
+		},
+	})
+	want := DeveloperMetadata{ID: 1, Key: "key", Value: "value", LocationType: "ROW", SheetID: 10, StartIndex: 1, EndIndex: 3}
+	if d != want {
+		t.Errorf("newDeveloperMetadata() = %+v, want %+v", d, want)
+	}
+}
+
+func TestDeleteDeveloperMetadataByEmptyKey(t *testing.T) {
+	err := Client{}.DeleteDeveloperMetadataByKey("spreadsheetID", "")
+	if err == nil || errors.Is(err, ErrServiceNotInitialized) {
+		t.Errorf("Empty key should be rejected before the request, got: %v", err)
+	}
+}
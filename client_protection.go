@@ -0,0 +1,146 @@
+package herschel
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// ProtectionOptions configures a protected range.
+type ProtectionOptions struct {
+	// Description is shown to users who try to edit the protected cells.
+	Description string
+	// WarningOnly shows a warning when editing instead of blocking edits.
+	// It can not be combined with editors.
+	WarningOnly bool
+	// EditorEmails are users and groups allowed to edit the protected cells.
+	EditorEmails []string
+	// DomainUsersCanEdit allows users of the spreadsheet owner's domain to edit the protected cells.
+	DomainUsersCanEdit bool
+}
+
+// ProtectSheet protects the whole sheet with title and returns ID of the protected range.
+func (client Client) ProtectSheet(spreadsheetID string, sheetTitle string, opts ProtectionOptions) (int64, error) {
+	sheetID, exists, err := getSheetID(client, spreadsheetID, sheetTitle)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, sheetNotFoundError(sheetTitle)
+	}
+	return client.addProtectedRange(spreadsheetID, &sheets.GridRange{SheetId: sheetID}, opts)
+}
+
+// ProtectRange protects cells in range of sheet with title and returns ID of the protected range.
+func (client Client) ProtectRange(spreadsheetID string, sheetTitle string, rowStart, colStart, numRows, numCols int, opts ProtectionOptions) (int64, error) {
+	if rowStart < 0 || colStart < 0 || numRows <= 0 || numCols <= 0 {
+		return 0, errors.Wrapf(ErrInvalidRange, "(%d, %d) %d x %d", rowStart, colStart, numRows, numCols)
+	}
+	sheetID, exists, err := getSheetID(client, spreadsheetID, sheetTitle)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, sheetNotFoundError(sheetTitle)
+	}
+	return client.addProtectedRange(spreadsheetID, &sheets.GridRange{
+		SheetId:          sheetID,
+		StartRowIndex:    int64(rowStart),
+		EndRowIndex:      int64(rowStart + numRows),
+		StartColumnIndex: int64(colStart),
+		EndColumnIndex:   int64(colStart + numCols),
+	}, opts)
+}
+
+// Unprotect deletes the protected range with ID.
+func (client Client) Unprotect(spreadsheetID string, protectedRangeID int64) error {
+	return client.batchUpdate(spreadsheetID, []*sheets.Request{deleteProtectedRangeRequest(protectedRangeID)})
+}
+
+func (client Client) addProtectedRange(spreadsheetID string, gridRange *sheets.GridRange, opts ProtectionOptions) (int64, error) {
+	req, err := addProtectedRangeRequest(gridRange, opts)
+	if err != nil {
+		return 0, err
+	}
+	replies, err := client.batchUpdateWithReplies(spreadsheetID, []*sheets.Request{req})
+	if err != nil {
+		return 0, err
+	}
+	if len(replies) == 0 || replies[0].AddProtectedRange == nil || replies[0].AddProtectedRange.ProtectedRange == nil {
+		return 0, errors.New("no protected range in response")
+	}
+	return replies[0].AddProtectedRange.ProtectedRange.ProtectedRangeId, nil
+}
+
+func addProtectedRangeRequest(gridRange *sheets.GridRange, opts ProtectionOptions) (*sheets.Request, error) {
+	hasEditors := len(opts.EditorEmails) > 0 || opts.DomainUsersCanEdit
+	if opts.WarningOnly && hasEditors {
+		return nil, errors.New("warning only protection can not have editors")
+	}
+
+	protectedRange := &sheets.ProtectedRange{
+		Range:       gridRange,
+		Description: opts.Description,
+		WarningOnly: opts.WarningOnly,
+	}
+	if !opts.WarningOnly {
+		// Without editors, only the owner (and the caller) can edit protected cells.
+		protectedRange.Editors = &sheets.Editors{
+			Users:              opts.EditorEmails,
+			DomainUsersCanEdit: opts.DomainUsersCanEdit,
+		}
+	}
+
+	return &sheets.Request{
+		AddProtectedRange: &sheets.AddProtectedRangeRequest{
+			ProtectedRange: protectedRange,
+		},
+	}, nil
+}
+
+func deleteProtectedRangeRequest(protectedRangeID int64) *sheets.Request {
+	return &sheets.Request{
+		DeleteProtectedRange: &sheets.DeleteProtectedRangeRequest{
+			ProtectedRangeId: protectedRangeID,
+			ForceSendFields:  []string{"ProtectedRangeId"},
+		},
+	}
+}
+
+// headerProtectionTag prefixes descriptions of header protections added by WriteTable,
+// so that they can be told apart from protections added by users.
+const headerProtectionTag = "[herschel header]"
+
+// headerProtectionRequests returns requests to protect header rows of table, replacing header protections added by previous writes.
+// When table has no protected rows, previous header protections are removed and nothing is added.
+func headerProtectionRequests(sheetID int64, existing []*sheets.ProtectedRange, table *Table) ([]*sheets.Request, error) {
+	requests := []*sheets.Request{}
+	for _, p := range existing {
+		if isHeaderProtection(p, sheetID) {
+			requests = append(requests, deleteProtectedRangeRequest(p.ProtectedRangeId))
+		}
+	}
+	if table.ProtectedRowCount <= 0 {
+		return requests, nil
+	}
+
+	opts := table.HeaderProtection
+	opts.Description = strings.TrimSpace(headerProtectionTag + " " + opts.Description)
+	req, err := addProtectedRangeRequest(&sheets.GridRange{
+		SheetId:     sheetID,
+		EndRowIndex: table.ProtectedRowCount,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return append(requests, req), nil
+}
+
+func isHeaderProtection(p *sheets.ProtectedRange, sheetID int64) bool {
+	if p.Range == nil || p.Range.SheetId != sheetID {
+		return false
+	}
+	return strings.HasPrefix(p.Description, headerProtectionTag)
+}
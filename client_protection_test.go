@@ -0,0 +1,128 @@
+package herschel
+
+import (
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestProtection(t *testing.T) {
+	spreadsheetID := createNewSpreadsheet(t)
+	c := newTestClient(t)
+
+	sheetTitle := t.Name()
+	if err := c.RecreateSheet(spreadsheetID, sheetTitle); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("ProtectSheet", func(t *testing.T) {
+		id, err := c.ProtectSheet(spreadsheetID, sheetTitle, ProtectionOptions{Description: "Generated", WarningOnly: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Unprotect(spreadsheetID, id); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("ProtectRange", func(t *testing.T) {
+		id, err := c.ProtectRange(spreadsheetID, sheetTitle, 1, 1, 2, 2, ProtectionOptions{Description: "Generated"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Unprotect(spreadsheetID, id); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("ProtectHeaderRows", func(t *testing.T) {
+		table := NewTable(2, 2)
+		table.PutValuesAtRow(0, "name", "value")
+		table.PutValuesAtRow(1, "foo", 1)
+
+		// Writing with different counts should not stack protections.
+		table.RemoveHeaderProtection = true
+		for _, count := range []int64{1, 2, 0} {
+			table.ProtectedRowCount = count
+			if err := c.WriteTable(spreadsheetID, sheetTitle, table); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestAddProtectedRangeRequest(t *testing.T) {
+	t.Run("WarningOnlyWithEditors", func(t *testing.T) {
+		if _, err := addProtectedRangeRequest(&sheets.GridRange{}, ProtectionOptions{WarningOnly: true, EditorEmails: []string{"foo@example.com"}}); err == nil {
+			t.Error("Warning only protection with editors should be an error.")
+		}
+	})
+
+	t.Run("Editors", func(t *testing.T) {
+		req, err := addProtectedRangeRequest(&sheets.GridRange{}, ProtectionOptions{Description: "desc", EditorEmails: []string{"foo@example.com"}, DomainUsersCanEdit: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := req.AddProtectedRange.ProtectedRange
+		if p.Description != "desc" {
+			t.Errorf("Description should be desc, got: %s", p.Description)
+		}
+		if p.Editors == nil || len(p.Editors.Users) != 1 || !p.Editors.DomainUsersCanEdit {
+			t.Errorf("Unexpected editors: %+v", p.Editors)
+		}
+	})
+}
+
+func TestHeaderProtectionRequests(t *testing.T) {
+	table := NewTable(3, 3)
+	table.ProtectedRowCount = 2
+	table.HeaderProtection = ProtectionOptions{Description: "Generated", WarningOnly: true}
+
+	existing := []*sheets.ProtectedRange{
+		{ProtectedRangeId: 1, Range: &sheets.GridRange{SheetId: 10, EndRowIndex: 1}, Description: headerProtectionTag},
+		{ProtectedRangeId: 2, Range: &sheets.GridRange{SheetId: 10, EndRowIndex: 2}, Description: "Added by user"},
+		{ProtectedRangeId: 3, Range: &sheets.GridRange{SheetId: 11, EndRowIndex: 2}, Description: headerProtectionTag},
+		{ProtectedRangeId: 4, Range: &sheets.GridRange{SheetId: 10, EndRowIndex: 3}, Description: headerProtectionTag + " Generated"},
+	}
+	requests, err := headerProtectionRequests(10, existing, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 3 {
+		t.Fatalf("3 requests expected, got: %d", len(requests))
+	}
+	for i, id := range []int64{1, 4} {
+		if requests[i].DeleteProtectedRange == nil || requests[i].DeleteProtectedRange.ProtectedRangeId != id {
+			t.Errorf("Request %d should delete protected range %d, got: %+v", i, id, requests[i])
+		}
+	}
+	added := requests[2].AddProtectedRange
+	if added == nil || added.ProtectedRange.Range.EndRowIndex != 2 {
+		t.Fatalf("Last request should protect 2 rows, got: %+v", requests[2])
+	}
+	if added.ProtectedRange.Description != headerProtectionTag+" Generated" {
+		t.Errorf("Unexpected description: %s", added.ProtectedRange.Description)
+	}
+
+	table.ProtectedRowCount = 0
+	requests, err = headerProtectionRequests(10, existing, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 || requests[0].DeleteProtectedRange == nil || requests[1].DeleteProtectedRange == nil {
+		t.Errorf("Only deletions of header protections expected without protected rows, got: %+v", requests)
+	}
+}
+
+func TestProtectHeaderRowsWithoutProtectedRows(t *testing.T) {
+	// No request should be made, so the uninitialized client does not fail.
+	if err := (Client{}).protectHeaderRows("spreadsheetID", 0, NewTable(1, 1)); err != nil {
+		t.Errorf("No request expected without protected rows, got: %v", err)
+	}
+
+	table := NewTable(1, 1)
+	table.RemoveHeaderProtection = true
+	if err := (Client{}).protectHeaderRows("spreadsheetID", 0, table); err == nil {
+		t.Error("Protected ranges should be read to remove header protection.")
+	}
+}
@@ -1,22 +1,84 @@
 package herschel
 
+import (
+	"image/color"
+
+	"github.com/pkg/errors"
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// SheetInfo represents metadata of sheet.
+type SheetInfo struct {
+	ID                int64
+	Title             string
+	Index             int
+	RowCount          int
+	ColumnCount       int
+	FrozenRowCount    int
+	FrozenColumnCount int
+	Hidden            bool
+	// TabColor is nil when sheet has no tab color.
+	TabColor color.Color
+	// Type is one of GRID, OBJECT or DATA_SOURCE.
+	Type string
+}
+
+// SpreadsheetInfo represents metadata of spreadsheet.
+type SpreadsheetInfo struct {
+	ID       string
+	Title    string
+	Locale   string
+	TimeZone string
+	URL      string
+}
+
 // Read returns a slice of cell values in sheet.
 func (client *Client) Read(spreadsheetID string, sheetTitle string) ([][]interface{}, error) {
-	resp, err := client.service.Spreadsheets.Values.Get(spreadsheetID, sheetTitle).Do()
+	s, err := client.spreadsheets()
 	if err != nil {
 		return nil, err
 	}
+	resp, err := s.Values.Get(spreadsheetID, sheetTitle).Do()
+	if err != nil {
+		return nil, client.valuesAPIError(spreadsheetID, sheetTitle, err)
+	}
 
 	return resp.Values, nil
 }
 
+// readUserEnteredValues returns values of sheet as the strings a user would enter.
+// Unlike Read, numbers are not formatted and formulas are read as they are, so writing the values back does not change the sheet.
+func (client Client) readUserEnteredValues(spreadsheetID string, sheetTitle string) ([][]interface{}, error) {
+	s, err := client.spreadsheets()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.Values.Get(spreadsheetID, sheetTitle).
+		ValueRenderOption("FORMULA").
+		DateTimeRenderOption("FORMATTED_STRING").
+		Do()
+	if err != nil {
+		return nil, client.valuesAPIError(spreadsheetID, sheetTitle, err)
+	}
+
+	for _, row := range resp.Values {
+		for i, v := range row {
+			row[i] = userEnteredString(v)
+		}
+	}
+	return resp.Values, nil
+}
+
 // ReadTable returns a table with values read from the spreadsheet set.
 func (client *Client) ReadTable(spreadsheetID string, sheetTitle string) (*Table, error) {
 	values, err := client.Read(spreadsheetID, sheetTitle)
 	if err != nil {
 		return nil, err
 	}
+	return tableFromValues(values), nil
+}
 
+func tableFromValues(values [][]interface{}) *Table {
 	maxCols := 0
 	for _, row := range values {
 		cols := len(row)
@@ -27,14 +89,144 @@ func (client *Client) ReadTable(spreadsheetID string, sheetTitle string) (*Table
 
 	t := NewTable(len(values), maxCols)
 	for i, row := range values {
-		for j, col := range row {
-			t.PutValue(i, j, col)
-		}
+		t.cells[i] = row
 	}
-	return t, nil
+	return t
 }
 
 // SheetTitles returns a slice of sheet titles.
 func (client Client) SheetTitles(spreadsheetID string) ([]string, error) {
 	return getSheetTitles(client, spreadsheetID)
 }
+
+// Sheets returns metadata of sheets in spreadsheet.
+func (client Client) Sheets(spreadsheetID string) ([]SheetInfo, error) {
+	properties, err := getSheetProperties(client, spreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []SheetInfo{}
+	for _, p := range properties {
+		infos = append(infos, newSheetInfo(p))
+	}
+	return infos, nil
+}
+
+// SpreadsheetInfo returns metadata of spreadsheet.
+func (client Client) SpreadsheetInfo(spreadsheetID string) (*SpreadsheetInfo, error) {
+	s, err := client.spreadsheets()
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := s.Get(spreadsheetID).Fields("spreadsheetId,spreadsheetUrl,properties(title,locale,timeZone)").Do()
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	info := &SpreadsheetInfo{
+		ID:  spreadsheet.SpreadsheetId,
+		URL: spreadsheet.SpreadsheetUrl,
+	}
+	if p := spreadsheet.Properties; p != nil {
+		info.Title = p.Title
+		info.Locale = p.Locale
+		info.TimeZone = p.TimeZone
+	}
+	return info, nil
+}
+
+func newSheetInfo(p *sheets.SheetProperties) SheetInfo {
+	info := SheetInfo{
+		ID:     p.SheetId,
+		Title:  p.Title,
+		Index:  int(p.Index),
+		Hidden: p.Hidden,
+		Type:   p.SheetType,
+	}
+	if g := p.GridProperties; g != nil {
+		info.RowCount = int(g.RowCount)
+		info.ColumnCount = int(g.ColumnCount)
+		info.FrozenRowCount = int(g.FrozenRowCount)
+		info.FrozenColumnCount = int(g.FrozenColumnCount)
+	}
+	if p.TabColor != nil {
+		info.TabColor = fromSheetsColor(p.TabColor)
+	} else if p.TabColorStyle != nil && p.TabColorStyle.RgbColor != nil {
+		info.TabColor = fromSheetsColor(p.TabColorStyle.RgbColor)
+	}
+	return info
+}
+
+// readGridTable returns a table with user entered values and formats of sheet, and properties of the sheet.
+func readGridTable(client Client, spreadsheetID string, sheetTitle string) (*Table, *sheets.SheetProperties, error) {
+	s, err := client.spreadsheets()
+	if err != nil {
+		return nil, nil, err
+	}
+	spreadsheet, err := s.Get(spreadsheetID).
+		Ranges(quoteSheetTitle(sheetTitle)).
+		IncludeGridData(true).
+		Fields("sheets(properties(sheetId,title,gridProperties),data(startRow,startColumn,rowData(values(userEnteredValue,userEnteredFormat(backgroundColor,numberFormat)))))").
+		Do()
+	if err != nil {
+		return nil, nil, client.valuesAPIError(spreadsheetID, sheetTitle, err)
+	}
+	if len(spreadsheet.Sheets) == 0 {
+		return nil, nil, errors.New("no sheet in response")
+	}
+	sheet := spreadsheet.Sheets[0]
+
+	rows, cols := 0, 0
+	for _, data := range sheet.Data {
+		if r := int(data.StartRow) + len(data.RowData); r > rows {
+			rows = r
+		}
+		for _, rowData := range data.RowData {
+			if c := int(data.StartColumn) + len(rowData.Values); c > cols {
+				cols = c
+			}
+		}
+	}
+
+	t := NewTable(rows, cols)
+	for _, data := range sheet.Data {
+		for i, rowData := range data.RowData {
+			row := int(data.StartRow) + i
+			for j, cell := range rowData.Values {
+				col := int(data.StartColumn) + j
+				if v := cell.UserEnteredValue; v != nil {
+					t.PutValue(row, col, fromExtendedValue(v))
+				}
+				if f := cell.UserEnteredFormat; f != nil {
+					if f.BackgroundColor != nil {
+						t.SetBackgroundColor(row, col, fromSheetsColor(f.BackgroundColor))
+					}
+					if f.NumberFormat != nil {
+						t.SetNumberFormatPattern(row, col, f.NumberFormat.Pattern)
+						t.SetNumberFormatType(row, col, f.NumberFormat.Type)
+					}
+				}
+			}
+		}
+	}
+	if g := sheet.Properties.GridProperties; g != nil {
+		t.FrozenRowCount = g.FrozenRowCount
+		t.FrozenColumnCount = g.FrozenColumnCount
+	}
+	return t, sheet.Properties, nil
+}
+
+func fromExtendedValue(v *sheets.ExtendedValue) interface{} {
+	switch {
+	case v.FormulaValue != nil:
+		return Formula(*v.FormulaValue)
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.NumberValue != nil:
+		return *v.NumberValue
+	case v.BoolValue != nil:
+		return *v.BoolValue
+	}
+	return nil
+}
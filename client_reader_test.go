@@ -47,4 +47,31 @@ func TestReading(t *testing.T) {
 		}
 	})
 
+	t.Run("SheetInfo", func(t *testing.T) {
+		infos, err := c.Sheets(spreadsheetID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(infos) != 2 {
+			t.Fatalf("Expect 2 sheets, got %d", len(infos))
+		}
+		info := infos[1]
+		if info.Title != sheetTitle || info.Index != 1 || info.Type != "GRID" {
+			t.Errorf("Unexpected sheet info: %+v", info)
+		}
+		if info.RowCount == 0 || info.ColumnCount == 0 {
+			t.Errorf("Grid size should not be empty: %+v", info)
+		}
+	})
+
+	t.Run("SpreadsheetInfo", func(t *testing.T) {
+		info, err := c.SpreadsheetInfo(spreadsheetID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.ID != spreadsheetID || len(info.Title) == 0 || len(info.URL) == 0 {
+			t.Errorf("Unexpected spreadsheet info: %+v", info)
+		}
+	})
+
 }
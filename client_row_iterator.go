@@ -0,0 +1,140 @@
+package herschel
+
+import (
+	"github.com/pkg/errors"
+)
+
+// RowIterator reads rows of sheet chunk by chunk, so that very large sheets can be processed without loading them into memory at once.
+//
+//	it := client.RowIterator(spreadsheetID, "Sheet1", 1000)
+//	for it.Next() {
+//		row := it.Row()
+//	}
+//	if err := it.Err(); err != nil {
+//		// Error handling
+//	}
+type RowIterator struct {
+	gridRows   func() (int, error)
+	fetch      func(readRange string) ([][]interface{}, error)
+	sheetTitle string
+	chunkRows  int
+
+	// rowCount is the number of rows in the grid of sheet, or -1 until it is read.
+	// Reading rows beyond the grid is an error of the api.
+	rowCount int
+	// nextRow is the index of the first row of the next chunk.
+	nextRow int
+	// pendingEmptyRows is the number of empty rows trimmed from the end of the last chunk.
+	// They are yielded only when a following chunk has values.
+	pendingEmptyRows int
+	buffer           [][]interface{}
+	index            int
+	row              []interface{}
+	done             bool
+	err              error
+}
+
+// RowIterator returns an iterator over rows of sheet, reading chunkRows rows per request.
+// Iteration stops at the first chunk without any values, or at the last row of the grid.
+func (client Client) RowIterator(spreadsheetID string, sheetTitle string, chunkRows int) *RowIterator {
+	gridRows := func() (int, error) {
+		properties, err := client.sheetPropertiesByTitle(spreadsheetID, sheetTitle)
+		if err != nil {
+			return 0, err
+		}
+		if properties.GridProperties == nil {
+			return 0, nil
+		}
+		return int(properties.GridProperties.RowCount), nil
+	}
+	return newRowIterator(sheetTitle, chunkRows, gridRows, func(readRange string) ([][]interface{}, error) {
+		s, err := client.spreadsheets()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.Values.Get(spreadsheetID, readRange).MajorDimension("ROWS").Do()
+		if err != nil {
+			return nil, client.valuesAPIError(spreadsheetID, sheetTitle, err)
+		}
+		return resp.Values, nil
+	})
+}
+
+func newRowIterator(sheetTitle string, chunkRows int, gridRows func() (int, error), fetch func(readRange string) ([][]interface{}, error)) *RowIterator {
+	it := &RowIterator{gridRows: gridRows, fetch: fetch, sheetTitle: sheetTitle, chunkRows: chunkRows, rowCount: -1, index: -1}
+	if chunkRows <= 0 {
+		it.err = errors.Errorf("chunk rows should be positive, got: %d", chunkRows)
+		it.done = true
+	}
+	return it
+}
+
+// Next advances the iterator to the next row. It returns false when there are no more rows or an error occurred.
+func (it *RowIterator) Next() bool {
+	for len(it.buffer) == 0 {
+		if it.done {
+			it.row = nil
+			return false
+		}
+		if err := it.readChunk(); err != nil {
+			it.err = err
+			it.done = true
+		}
+	}
+
+	it.row = it.buffer[0]
+	it.buffer = it.buffer[1:]
+	it.index++
+	return true
+}
+
+// Row returns values of the current row. Trailing empty cells are omitted.
+func (it *RowIterator) Row() []interface{} {
+	return it.row
+}
+
+// Index returns index of the current row in sheet.
+func (it *RowIterator) Index() int {
+	return it.index
+}
+
+// Err returns the error occurred during iteration, if any.
+func (it *RowIterator) Err() error {
+	return it.err
+}
+
+func (it *RowIterator) readChunk() error {
+	if it.rowCount < 0 {
+		rowCount, err := it.gridRows()
+		if err != nil {
+			return errors.Wrap(err, "failed to read grid limits")
+		}
+		it.rowCount = rowCount
+	}
+	numRows := it.rowCount - it.nextRow
+	if numRows > it.chunkRows {
+		numRows = it.chunkRows
+	}
+	if numRows <= 0 {
+		it.done = true
+		return nil
+	}
+
+	values, err := it.fetch(rowsRange(it.sheetTitle, it.nextRow, numRows))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read rows from %d", it.nextRow)
+	}
+	it.nextRow += numRows
+
+	if len(values) == 0 {
+		it.done = true
+		return nil
+	}
+
+	for i := 0; i < it.pendingEmptyRows; i++ {
+		it.buffer = append(it.buffer, []interface{}{})
+	}
+	it.buffer = append(it.buffer, values...)
+	it.pendingEmptyRows = numRows - len(values)
+	return nil
+}
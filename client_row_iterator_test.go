@@ -0,0 +1,118 @@
+package herschel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestRowIterator(t *testing.T) {
+	chunks := map[string][][]interface{}{
+		"'Sheet1'!1:2": {{"a"}, {"b"}},
+		// Trailing empty rows are omitted by the api.
+		"'Sheet1'!3:4": {{"c"}},
+		"'Sheet1'!5:6": {{}, {"d"}},
+	}
+	requested := []string{}
+	it := newRowIterator("Sheet1", 2, gridRowsOf(1000), func(readRange string) ([][]interface{}, error) {
+		requested = append(requested, readRange)
+		return chunks[readRange], nil
+	})
+
+	rows := [][]interface{}{}
+	for it.Next() {
+		if it.Index() != len(rows) {
+			t.Errorf("Index should be %d, got: %d", len(rows), it.Index())
+		}
+		rows = append(rows, it.Row())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]interface{}{{"a"}, {"b"}, {"c"}, {}, {}, {"d"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Unexpected rows: %v", rows)
+	}
+	if len(requested) != 4 || requested[3] != "'Sheet1'!7:8" {
+		t.Errorf("Iteration should stop at the first empty chunk: %v", requested)
+	}
+}
+
+func TestRowIteratorGridLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		rowCount  int
+		requested []string
+		rows      int
+	}{
+		{"EndsOnChunkBoundary", 4, []string{"'Sheet1'!1:2", "'Sheet1'!3:4"}, 4},
+		{"EndsInChunk", 3, []string{"'Sheet1'!1:2", "'Sheet1'!3:3"}, 3},
+		{"EmptyGrid", 0, []string{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested := []string{}
+			it := newRowIterator("Sheet1", 2, gridRowsOf(tt.rowCount), func(readRange string) ([][]interface{}, error) {
+				requested = append(requested, readRange)
+				if len(requested) > len(tt.requested) {
+					return nil, errors.Wrap(ErrInvalidRange, "exceeds grid limits")
+				}
+				// Every row of the grid has a value.
+				values := [][]interface{}{{"a"}}
+				if readRange != "'Sheet1'!3:3" {
+					values = append(values, []interface{}{"b"})
+				}
+				return values, nil
+			})
+
+			rows := 0
+			for it.Next() {
+				rows++
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if rows != tt.rows || !reflect.DeepEqual(requested, tt.requested) {
+				t.Errorf("Unexpected iteration: %d rows, requested %v", rows, requested)
+			}
+		})
+	}
+}
+
+func TestRowIteratorError(t *testing.T) {
+	it := newRowIterator("Sheet1", 2, gridRowsOf(1000), func(readRange string) ([][]interface{}, error) {
+		if readRange == "'Sheet1'!1:2" {
+			return [][]interface{}{{"a"}, {"b"}}, nil
+		}
+		return nil, ErrQuotaExceeded
+	})
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("Rows before the error should be yielded, got: %d", count)
+	}
+	if !errors.Is(it.Err(), ErrQuotaExceeded) {
+		t.Errorf("ErrQuotaExceeded expected, got: %v", it.Err())
+	}
+
+	it = newRowIterator("Sheet1", 2, func() (int, error) { return 0, ErrSheetNotFound }, nil)
+	if it.Next() || !errors.Is(it.Err(), ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected, got: %v", it.Err())
+	}
+
+	it = newRowIterator("Sheet1", 0, nil, nil)
+	if it.Next() || it.Err() == nil {
+		t.Error("Iterator with invalid chunk size should fail.")
+	}
+}
+
+func gridRowsOf(rowCount int) func() (int, error) {
+	return func() (int, error) {
+		return rowCount, nil
+	}
+}
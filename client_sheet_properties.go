@@ -0,0 +1,141 @@
+package herschel
+
+import (
+	"image/color"
+	"strings"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// SheetProperty is a name of sheet property to be updated by UpdateSheetProperties.
+type SheetProperty string
+
+// Sheet properties which can be updated by UpdateSheetProperties.
+const (
+	SheetPropertyTitle             SheetProperty = "title"
+	SheetPropertyIndex             SheetProperty = "index"
+	SheetPropertyHidden            SheetProperty = "hidden"
+	SheetPropertyTabColor          SheetProperty = "tabColor"
+	SheetPropertyRightToLeft       SheetProperty = "rightToLeft"
+	SheetPropertyRowCount          SheetProperty = "gridProperties.rowCount"
+	SheetPropertyColumnCount       SheetProperty = "gridProperties.columnCount"
+	SheetPropertyFrozenRowCount    SheetProperty = "gridProperties.frozenRowCount"
+	SheetPropertyFrozenColumnCount SheetProperty = "gridProperties.frozenColumnCount"
+)
+
+// SheetProperties represents properties of sheet.
+type SheetProperties struct {
+	Title             string
+	Index             int
+	Hidden            bool
+	TabColor          color.Color
+	RightToLeft       bool
+	RowCount          int
+	ColumnCount       int
+	FrozenRowCount    int
+	FrozenColumnCount int
+}
+
+// UpdateSheetProperties updates properties of sheet with title. Only properties listed in fields are updated.
+func (client Client) UpdateSheetProperties(spreadsheetID string, sheetTitle string, properties SheetProperties, fields ...SheetProperty) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	sheetID, exists, err := getSheetID(client, spreadsheetID, sheetTitle)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sheetNotFoundError(sheetTitle)
+	}
+	for _, f := range fields {
+		if f == SheetPropertyTitle {
+			defer client.cache.invalidate(spreadsheetID)
+			break
+		}
+	}
+	return client.batchUpdate(spreadsheetID, []*sheets.Request{updateSheetPropertiesRequest(sheetID, properties, fields)})
+}
+
+// RenameSheet changes title of sheet.
+func (client Client) RenameSheet(spreadsheetID string, sheetTitle string, newTitle string) error {
+	return client.UpdateSheetProperties(spreadsheetID, sheetTitle, SheetProperties{Title: newTitle}, SheetPropertyTitle)
+}
+
+// MoveSheet moves sheet to index.
+func (client Client) MoveSheet(spreadsheetID string, sheetTitle string, index int) error {
+	return client.UpdateSheetProperties(spreadsheetID, sheetTitle, SheetProperties{Index: index}, SheetPropertyIndex)
+}
+
+// HideSheet hides sheet.
+func (client Client) HideSheet(spreadsheetID string, sheetTitle string) error {
+	return client.UpdateSheetProperties(spreadsheetID, sheetTitle, SheetProperties{Hidden: true}, SheetPropertyHidden)
+}
+
+// ShowSheet shows hidden sheet.
+func (client Client) ShowSheet(spreadsheetID string, sheetTitle string) error {
+	return client.UpdateSheetProperties(spreadsheetID, sheetTitle, SheetProperties{Hidden: false}, SheetPropertyHidden)
+}
+
+// SetTabColor sets tab color of sheet. color.Transparent clears tab color.
+func (client Client) SetTabColor(spreadsheetID string, sheetTitle string, c color.Color) error {
+	return client.UpdateSheetProperties(spreadsheetID, sheetTitle, SheetProperties{TabColor: c}, SheetPropertyTabColor)
+}
+
+// SetRightToLeft sets whether sheet is right to left.
+func (client Client) SetRightToLeft(spreadsheetID string, sheetTitle string, rightToLeft bool) error {
+	return client.UpdateSheetProperties(spreadsheetID, sheetTitle, SheetProperties{RightToLeft: rightToLeft}, SheetPropertyRightToLeft)
+}
+
+func updateSheetPropertiesRequest(sheetID int64, properties SheetProperties, fields []SheetProperty) *sheets.Request {
+	p := &sheets.SheetProperties{SheetId: sheetID}
+	mask := []string{}
+
+	for _, f := range fields {
+		switch f {
+		case SheetPropertyTitle:
+			p.Title = properties.Title
+		case SheetPropertyIndex:
+			p.Index = int64(properties.Index)
+			p.ForceSendFields = append(p.ForceSendFields, "Index")
+		case SheetPropertyHidden:
+			p.Hidden = properties.Hidden
+			p.ForceSendFields = append(p.ForceSendFields, "Hidden")
+		case SheetPropertyTabColor:
+			// Leaving tabColor empty with the field mask clears tab color.
+			if properties.TabColor != nil && properties.TabColor != color.Transparent {
+				p.TabColor = toSheetsColor(properties.TabColor)
+			}
+		case SheetPropertyRightToLeft:
+			p.RightToLeft = properties.RightToLeft
+			p.ForceSendFields = append(p.ForceSendFields, "RightToLeft")
+		case SheetPropertyRowCount, SheetPropertyColumnCount, SheetPropertyFrozenRowCount, SheetPropertyFrozenColumnCount:
+			if p.GridProperties == nil {
+				p.GridProperties = &sheets.GridProperties{}
+			}
+			g := p.GridProperties
+			switch f {
+			case SheetPropertyRowCount:
+				g.RowCount = int64(properties.RowCount)
+				g.ForceSendFields = append(g.ForceSendFields, "RowCount")
+			case SheetPropertyColumnCount:
+				g.ColumnCount = int64(properties.ColumnCount)
+				g.ForceSendFields = append(g.ForceSendFields, "ColumnCount")
+			case SheetPropertyFrozenRowCount:
+				g.FrozenRowCount = int64(properties.FrozenRowCount)
+				g.ForceSendFields = append(g.ForceSendFields, "FrozenRowCount")
+			case SheetPropertyFrozenColumnCount:
+				g.FrozenColumnCount = int64(properties.FrozenColumnCount)
+				g.ForceSendFields = append(g.ForceSendFields, "FrozenColumnCount")
+			}
+		}
+		mask = append(mask, string(f))
+	}
+
+	return &sheets.Request{
+		UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+			Properties: p,
+			Fields:     strings.Join(mask, ","),
+		},
+	}
+}
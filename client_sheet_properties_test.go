@@ -0,0 +1,76 @@
+package herschel
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestSheetProperties(t *testing.T) {
+	spreadsheetID := createNewSpreadsheet(t)
+	c := newTestClient(t)
+
+	sheetTitle := t.Name()
+	if err := c.RecreateSheet(spreadsheetID, sheetTitle); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.SetTabColor(spreadsheetID, sheetTitle, color.RGBA{255, 0, 0, 255}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetRightToLeft(spreadsheetID, sheetTitle, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MoveSheet(spreadsheetID, sheetTitle, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.HideSheet(spreadsheetID, sheetTitle); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ShowSheet(spreadsheetID, sheetTitle); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := sheetTitle + "Renamed"
+	if err := c.RenameSheet(spreadsheetID, sheetTitle, renamed); err != nil {
+		t.Fatal(err)
+	}
+	titles, err := c.SheetTitles(spreadsheetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if titles[0] != renamed {
+		t.Errorf("First sheet should be %s, got: %v", renamed, titles)
+	}
+}
+
+func TestUpdateSheetPropertiesRequest(t *testing.T) {
+	req := updateSheetPropertiesRequest(1, SheetProperties{Title: "foo", Index: 0, RowCount: 10}, []SheetProperty{SheetPropertyTitle, SheetPropertyIndex, SheetPropertyRowCount}).UpdateSheetProperties
+
+	if req.Fields != "title,index,gridProperties.rowCount" {
+		t.Errorf("Unexpected fields: %s", req.Fields)
+	}
+	if req.Properties.SheetId != 1 || req.Properties.Title != "foo" {
+		t.Errorf("Unexpected properties: %+v", req.Properties)
+	}
+	if req.Properties.GridProperties == nil || req.Properties.GridProperties.RowCount != 10 {
+		t.Errorf("Unexpected grid properties: %+v", req.Properties.GridProperties)
+	}
+
+	b, err := req.Properties.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != `{"gridProperties":{"rowCount":10},"index":0,"sheetId":1,"title":"foo"}` {
+		t.Errorf("Zero index should be sent, got: %s", got)
+	}
+
+	t.Run("ClearTabColor", func(t *testing.T) {
+		req := updateSheetPropertiesRequest(1, SheetProperties{TabColor: color.Transparent}, []SheetProperty{SheetPropertyTabColor}).UpdateSheetProperties
+		if req.Properties.TabColor != nil {
+			t.Errorf("Tab color should be empty, got: %+v", req.Properties.TabColor)
+		}
+		if req.Fields != "tabColor" {
+			t.Errorf("Unexpected fields: %s", req.Fields)
+		}
+	})
+}
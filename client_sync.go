@@ -0,0 +1,190 @@
+package herschel
+
+import (
+	"image/color"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// SyncResult is a summary of changes made by SyncTable.
+type SyncResult struct {
+	// UpdatedValues is the number of cells whose values were written.
+	UpdatedValues int
+	// UpdatedFormats is the number of cells whose formats were written.
+	UpdatedFormats int
+	// ValueRanges are A1 notation ranges whose values were written.
+	ValueRanges []string
+	// UpdatedSheetProperties reports whether grid size or frozen rows / cols were changed.
+	UpdatedSheetProperties bool
+}
+
+// SyncTable updates sheet to match the desired table, writing only cells whose values or formats differ.
+// Cells outside of the desired table are cleared.
+func (client Client) SyncTable(spreadsheetID string, sheetTitle string, desired *Table) (*SyncResult, error) {
+	current, properties, err := readGridTable(client, spreadsheetID, sheetTitle)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{}
+	changes := cellChanges(current, desired)
+
+	data := valueRangesForChanges(sheetTitle, desired, changes)
+	if len(data) > 0 {
+		if err := client.batchUpdateValues(spreadsheetID, sheetTitle, data); err != nil {
+			return nil, err
+		}
+		for _, d := range data {
+			result.ValueRanges = append(result.ValueRanges, d.Range)
+			result.UpdatedValues += len(d.Values[0])
+		}
+	}
+
+	requests := []*sheets.Request{}
+	if req := syncSheetPropertiesRequest(properties, desired); req != nil {
+		requests = append(requests, req)
+		result.UpdatedSheetProperties = true
+	}
+	for _, change := range changes {
+		if change.format {
+			requests = append(requests, cellFormatRequest(properties.SheetId, desired, change.row, change.col))
+			result.UpdatedFormats++
+		}
+	}
+	if err := client.batchUpdate(spreadsheetID, requests); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type cellChange struct {
+	row    int
+	col    int
+	value  bool
+	format bool
+}
+
+// cellChanges returns cells whose values or formats differ between tables, in row-major order.
+func cellChanges(current *Table, desired *Table) []cellChange {
+	rows := current.rows
+	if desired.rows > rows {
+		rows = desired.rows
+	}
+	cols := current.cols
+	if desired.cols > cols {
+		cols = desired.cols
+	}
+
+	changes := []cellChange{}
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			c := cellChange{
+				row:    row,
+				col:    col,
+				value:  !sameCellValue(current, row, desired, row, col),
+				format: !sameCellFormat(current, row, desired, row, col),
+			}
+			if c.value || c.format {
+				changes = append(changes, c)
+			}
+		}
+	}
+	return changes
+}
+
+// valueRangesForChanges groups value changes in a row into contiguous ranges.
+func valueRangesForChanges(sheetTitle string, desired *Table, changes []cellChange) []*sheets.ValueRange {
+	data := []*sheets.ValueRange{}
+	var run []interface{}
+	runRow, runCol := -1, -1
+
+	flush := func() {
+		if len(run) > 0 {
+			data = append(data, &sheets.ValueRange{
+				Range:          cellRange(sheetTitle, runRow, runCol, 1, len(run)),
+				MajorDimension: "ROWS",
+				Values:         [][]interface{}{run},
+			})
+		}
+		run = nil
+	}
+
+	for _, change := range changes {
+		if !change.value {
+			continue
+		}
+		if change.row != runRow || change.col != runCol+len(run) {
+			flush()
+			runRow, runCol = change.row, change.col
+		}
+		v := desired.GetValue(change.row, change.col)
+		if v == nil {
+			// Empty string clears the cell. nil leaves it unchanged.
+			v = ""
+		}
+		run = append(run, v)
+	}
+	flush()
+	return data
+}
+
+func cellFormatRequest(sheetID int64, table *Table, row int, col int) *sheets.Request {
+	format := &sheets.CellFormat{}
+	if c := table.getBackgroundColor(row, col); c != color.Transparent {
+		format.BackgroundColor = toSheetsColor(c)
+	}
+	if p := table.getNumberFormatPattern(row, col); len(p) > 0 {
+		format.NumberFormat = &sheets.NumberFormat{
+			Type:    effectiveNumberFormatType(table, row, col),
+			Pattern: p,
+		}
+	}
+
+	// Fields without values are cleared.
+	return &sheets.Request{
+		RepeatCell: &sheets.RepeatCellRequest{
+			Range: &sheets.GridRange{SheetId: sheetID,
+				StartColumnIndex: int64(col),
+				EndColumnIndex:   int64(col) + 1,
+				StartRowIndex:    int64(row),
+				EndRowIndex:      int64(row) + 1,
+			},
+			Cell:   &sheets.CellData{UserEnteredFormat: format},
+			Fields: "userEnteredFormat(backgroundColor,numberFormat)",
+		},
+	}
+}
+
+// syncSheetPropertiesRequest returns a request to expand grid and update frozen rows / cols of sheet, or nil if nothing changes.
+func syncSheetPropertiesRequest(properties *sheets.SheetProperties, desired *Table) *sheets.Request {
+	g := properties.GridProperties
+	if g == nil {
+		g = &sheets.GridProperties{}
+	}
+
+	p := SheetProperties{
+		RowCount:          int(g.RowCount),
+		ColumnCount:       int(g.ColumnCount),
+		FrozenRowCount:    int(desired.FrozenRowCount),
+		FrozenColumnCount: int(desired.FrozenColumnCount),
+	}
+	fields := []SheetProperty{}
+	if desired.rows > p.RowCount {
+		p.RowCount = desired.rows
+		fields = append(fields, SheetPropertyRowCount)
+	}
+	if desired.cols > p.ColumnCount {
+		p.ColumnCount = desired.cols
+		fields = append(fields, SheetPropertyColumnCount)
+	}
+	if desired.FrozenRowCount != g.FrozenRowCount {
+		fields = append(fields, SheetPropertyFrozenRowCount)
+	}
+	if desired.FrozenColumnCount != g.FrozenColumnCount {
+		fields = append(fields, SheetPropertyFrozenColumnCount)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return updateSheetPropertiesRequest(properties.SheetId, p, fields)
+}
@@ -0,0 +1,123 @@
+package herschel
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestSyncTable(t *testing.T) {
+	spreadsheetID := createNewSpreadsheet(t)
+	c := newTestClient(t)
+
+	sheetTitle := t.Name()
+	if err := c.RecreateSheet(spreadsheetID, sheetTitle); err != nil {
+		t.Fatal(err)
+	}
+
+	table := NewTable(3, 2)
+	table.PutValuesAtRow(0, "name", "value")
+	table.PutValuesAtRow(1, "foo", 1)
+	table.PutValuesAtRow(2, "bar", 2)
+	if err := c.WriteTable(spreadsheetID, sheetTitle, table); err != nil {
+		t.Fatal(err)
+	}
+
+	table.PutValue(2, 1, 3)
+	table.SetBackgroundColor(0, 0, color.Black)
+	result, err := c.SyncTable(spreadsheetID, sheetTitle, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.UpdatedValues != 1 || result.UpdatedFormats != 1 {
+		t.Errorf("1 value and 1 format update expected, got: %+v", result)
+	}
+
+	result, err = c.SyncTable(spreadsheetID, sheetTitle, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.UpdatedValues != 0 || result.UpdatedFormats != 0 {
+		t.Errorf("No updates expected after sync, got: %+v", result)
+	}
+}
+
+func TestCellChanges(t *testing.T) {
+	current := NewTable(2, 3)
+	current.PutValuesAtRow(0, "a", float64(1), true)
+	current.PutValuesAtRow(1, "b", float64(2), "=SUM(B1:B2)")
+	current.SetBackgroundColor(1, 0, color.RGBA{255, 0, 0, 255})
+
+	desired := NewTable(3, 3)
+	desired.PutValuesAtRow(0, "a", 1, true)
+	desired.PutValuesAtRow(1, "b", 3, "=SUM(B1:B2)")
+	desired.PutValuesAtRow(2, "c")
+	desired.SetBackgroundColor(1, 0, color.RGBA{255, 0, 0, 0})
+	desired.SetNumberFormatPattern(0, 1, "#,##0")
+
+	want := []cellChange{
+		{row: 0, col: 1, format: true},
+		{row: 1, col: 1, value: true},
+		{row: 2, col: 0, value: true},
+	}
+	if got := cellChanges(current, desired); !reflect.DeepEqual(got, want) {
+		t.Errorf("cellChanges() = %+v, want %+v", got, want)
+	}
+
+	t.Run("ClearingCellsOutsideOfDesiredTable", func(t *testing.T) {
+		want := []cellChange{
+			{row: 2, col: 0, value: true},
+		}
+		if got := cellChanges(desired, current); len(got) != 3 || !reflect.DeepEqual(got[2], want[0]) {
+			t.Errorf("cellChanges() = %+v, want last change %+v", got, want[0])
+		}
+	})
+}
+
+func TestValueRangesForChanges(t *testing.T) {
+	desired := NewTable(2, 4)
+	desired.PutValuesAtRow(0, "a", "b", "c", "d")
+	desired.PutValuesAtRow(1, "e")
+
+	changes := []cellChange{
+		{row: 0, col: 0, value: true},
+		{row: 0, col: 1, value: true},
+		{row: 0, col: 2, format: true},
+		{row: 0, col: 3, value: true},
+		{row: 1, col: 0, value: true},
+		{row: 1, col: 1, value: true},
+	}
+	data := valueRangesForChanges("Sheet1", desired, changes)
+
+	want := []*sheets.ValueRange{
+		{Range: "'Sheet1'!A1:B1", MajorDimension: "ROWS", Values: [][]interface{}{{"a", "b"}}},
+		{Range: "'Sheet1'!D1", MajorDimension: "ROWS", Values: [][]interface{}{{"d"}}},
+		{Range: "'Sheet1'!A2:B2", MajorDimension: "ROWS", Values: [][]interface{}{{"e", ""}}},
+	}
+	if !reflect.DeepEqual(data, want) {
+		for _, d := range data {
+			t.Logf("%+v", d)
+		}
+		t.Error("Unexpected value ranges.")
+	}
+}
+
+func TestSyncSheetPropertiesRequest(t *testing.T) {
+	desired := NewTable(3, 30)
+	properties := &sheets.SheetProperties{SheetId: 1, GridProperties: &sheets.GridProperties{RowCount: 1000, ColumnCount: 26}}
+
+	req := syncSheetPropertiesRequest(properties, desired)
+	if req == nil {
+		t.Fatal("Grid should be expanded.")
+	}
+	if fields := req.UpdateSheetProperties.Fields; fields != "gridProperties.columnCount" {
+		t.Errorf("Unexpected fields: %s", fields)
+	}
+
+	desired = NewTable(3, 3)
+	if req := syncSheetPropertiesRequest(properties, desired); req != nil {
+		t.Errorf("No request expected, got: %+v", req.UpdateSheetProperties)
+	}
+}
@@ -1,14 +1,13 @@
 package herschel
 
 import (
-	"fmt"
-
+	"github.com/pkg/errors"
 	"google.golang.org/api/sheets/v4"
 )
 
 // Write writes values to spreadsheet
 func (client Client) Write(spreadsheetID string, sheetTitle string, values [][]interface{}) error {
-	return client.updateCellValues(spreadsheetID, sheetTitle, values)
+	return client.updateCellValues(spreadsheetID, sheetTitle, sheetTitle, values)
 }
 
 // WriteTable writes values of table to spreadsheet
@@ -19,6 +18,77 @@ func (client Client) WriteTable(spreadsheetID string, sheetTitle string, table *
 	return client.setCellFormats(spreadsheetID, sheetTitle, table)
 }
 
+// WriteTableAt writes values and cell formats of table to spreadsheet with the top-left cell of table at (row, col).
+// References of formulas in table are shifted by the offset. Frozen rows, frozen columns and protection are not written.
+func (client Client) WriteTableAt(spreadsheetID string, sheetTitle string, table *Table, row int, col int) error {
+	if row < 0 || col < 0 {
+		return errors.Wrapf(ErrInvalidRange, "invalid offset (%d, %d)", row, col)
+	}
+	if table.rows == 0 || table.cols == 0 {
+		return nil
+	}
+
+	moved := table.withOffset(row, col)
+	values := make([][]interface{}, table.rows)
+	for i := range values {
+		values[i] = moved.GetValuesAtRow(row + i)[col:]
+	}
+	if err := client.updateCellValues(spreadsheetID, sheetTitle, cellRange(sheetTitle, row, col, table.rows, table.cols), values); err != nil {
+		return err
+	}
+
+	sheetID, exists, err := getSheetID(client, spreadsheetID, sheetTitle)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sheetNotFoundError(sheetTitle)
+	}
+	requests := columnWidthRequests(sheetID, moved)
+	requests = append(requests, rowFormatRequests(sheetID, moved, row, row+table.rows)...)
+	return client.batchUpdate(spreadsheetID, requests)
+}
+
+// UpsertRows updates rows of sheet whose value in the column with header keyHeader matches the given rows, and appends the others.
+// The first row of sheet is the header row and is never updated. Only the given values are written, so other cells are left unchanged.
+func (client Client) UpsertRows(spreadsheetID string, sheetTitle string, keyHeader string, rows [][]interface{}) (*UpsertResult, error) {
+	// Keys are matched with unformatted values, since formatted numbers such as "1,234" never match the given keys.
+	values, err := client.readUserEnteredValues(spreadsheetID, sheetTitle)
+	if err != nil {
+		return nil, err
+	}
+	table := tableFromValues(values)
+
+	keyCol := -1
+	for col := 0; col < table.GetCols(); col++ {
+		if table.GetStringValue(0, col) == keyHeader {
+			keyCol = col
+			break
+		}
+	}
+	if keyCol < 0 {
+		return nil, errors.Wrapf(ErrHeaderNotFound, "header %s in sheet %s", keyHeader, sheetTitle)
+	}
+
+	result := table.UpsertBelowHeader(1, []int{keyCol}, rows...)
+
+	data := []*sheets.ValueRange{}
+	for i, values := range rows {
+		if len(values) == 0 {
+			continue
+		}
+		data = append(data, &sheets.ValueRange{
+			Range:          cellRange(sheetTitle, result.Rows[i], 0, 1, len(values)),
+			MajorDimension: "ROWS",
+			Values:         [][]interface{}{values},
+		})
+	}
+	if err := client.batchUpdateValues(spreadsheetID, sheetTitle, data); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // AddSheet adds new sheet with title
 func (client Client) AddSheet(spreadsheetID string, sheetTitle string) error {
 	return addSheet(client, spreadsheetID, sheetTitle)
@@ -41,35 +111,46 @@ func (client Client) RecreateSheet(spreadsheetID string, sheetTitle string) erro
 	return recreateSheet(client, spreadsheetID, sheetTitle)
 }
 
-// ClearSheetValues clears values of sheet.
-func (client Client) ClearSheetValues(spreadsheetID string, sheetTitle string) error {
-	_, err := client.service.Spreadsheets.Values.Clear(spreadsheetID, sheetTitle, &sheets.ClearValuesRequest{}).Do()
-	return err
+// DuplicateSheet duplicates a sheet with title in the same spreadsheet. Negative index inserts the new sheet after the source sheet.
+func (client Client) DuplicateSheet(spreadsheetID string, sourceTitle string, newTitle string, index int) error {
+	sheetID, exists, err := getSheetID(client, spreadsheetID, sourceTitle)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sheetNotFoundError(sourceTitle)
+	}
+	return duplicateSheet(client, spreadsheetID, sheetID, newTitle, index)
 }
 
-// UpdateSheetGridLimits updates grid limits of sheet.
-func (client Client) UpdateSheetGridLimits(spreadsheetID string, sheetTitle string, rows int, columns int) error {
-	sheetID, exists, err := getSheetID(client, spreadsheetID, sheetTitle)
+// CopySheetTo copies a sheet with title to another spreadsheet and names it newTitle.
+func (client Client) CopySheetTo(srcSpreadsheetID string, sheetTitle string, dstSpreadsheetID string, newTitle string) error {
+	sheetID, exists, err := getSheetID(client, srcSpreadsheetID, sheetTitle)
 	if err != nil {
 		return err
 	}
 	if !exists {
-		return fmt.Errorf("sheet with title %s not found", sheetTitle)
-	}
-
-	return client.batchUpdate(spreadsheetID, []*sheets.Request{
-		&sheets.Request{
-			UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
-				Fields: "*",
-				Properties: &sheets.SheetProperties{
-					SheetId: sheetID,
-					Title:   sheetTitle,
-					GridProperties: &sheets.GridProperties{
-						ColumnCount: int64(columns),
-						RowCount:    int64(rows),
-					},
-				},
-			},
-		},
-	})
+		return sheetNotFoundError(sheetTitle)
+	}
+	return copySheetTo(client, srcSpreadsheetID, sheetID, dstSpreadsheetID, newTitle)
+}
+
+// ClearSheetValues clears values of sheet.
+func (client Client) ClearSheetValues(spreadsheetID string, sheetTitle string) error {
+	s, err := client.spreadsheets()
+	if err != nil {
+		return err
+	}
+	if _, err := s.Values.Clear(spreadsheetID, sheetTitle, &sheets.ClearValuesRequest{}).Do(); err != nil {
+		return client.valuesAPIError(spreadsheetID, sheetTitle, err)
+	}
+	return nil
+}
+
+// UpdateSheetGridLimits updates grid limits of sheet.
+func (client Client) UpdateSheetGridLimits(spreadsheetID string, sheetTitle string, rows int, columns int) error {
+	return client.UpdateSheetProperties(spreadsheetID, sheetTitle, SheetProperties{
+		RowCount:    rows,
+		ColumnCount: columns,
+	}, SheetPropertyRowCount, SheetPropertyColumnCount)
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/yokoe/herschel/option"
 )
 
@@ -99,6 +100,82 @@ func TestWriting(t *testing.T) {
 		}
 	})
 
+	t.Run("Write table at offset", func(t *testing.T) {
+		sheetTitle := t.Name()
+		if err := c.RecreateSheet(spreadsheetID, sheetTitle); err != nil {
+			t.Fatal(err)
+		}
+
+		table := NewTable(3, 1)
+		table.PutValue(0, 0, 1)
+		table.PutValue(1, 0, 2)
+		table.PutValue(2, 0, Formula("=SUM(A1:A2)"))
+
+		if err := c.WriteTableAt(spreadsheetID, sheetTitle, table, 2, 1); err != nil {
+			t.Fatal(err)
+		}
+
+		written, err := c.ReadTable(spreadsheetID, sheetTitle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := userEnteredString(written.GetValue(4, 1)); got != "3" {
+			t.Errorf("Unexpected result of formula at B5: %s", got)
+		}
+	})
+}
+
+func TestUpsertRows(t *testing.T) {
+	spreadsheetID := createNewSpreadsheet(t)
+	c := newTestClient(t)
+
+	sheetTitle := t.Name()
+	if err := c.RecreateSheet(spreadsheetID, sheetTitle); err != nil {
+		t.Fatal(err)
+	}
+	table := NewTable(2, 2)
+	table.PutValuesAtRow(0, "id", "name")
+	table.PutValuesAtRow(1, "a", "foo")
+	if err := c.WriteTable(spreadsheetID, sheetTitle, table); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.UpsertRows(spreadsheetID, sheetTitle, "id", [][]interface{}{{"a", "bar"}, {"b", "baz"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updated != 1 || result.Inserted != 1 {
+		t.Errorf("1 updated and 1 inserted expected, got: %+v", result)
+	}
+
+	read, err := c.ReadTable(spreadsheetID, sheetTitle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.GetValue(1, 1) != "bar" || read.GetValue(2, 1) != "baz" {
+		t.Errorf("Unexpected values: %v", read.Values())
+	}
+
+	t.Run("FormattedKey", func(t *testing.T) {
+		table := NewTable(2, 2)
+		table.PutValuesAtRow(0, "id", "name")
+		table.PutValuesAtRow(1, 1234, "foo")
+		table.SetNumberFormatPattern(1, 0, "#,##0")
+		if err := c.WriteTable(spreadsheetID, sheetTitle, table); err != nil {
+			t.Fatal(err)
+		}
+		result, err := c.UpsertRows(spreadsheetID, sheetTitle, "id", [][]interface{}{{1234, "bar"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Updated != 1 || result.Inserted != 0 {
+			t.Errorf("Row with key formatted as 1,234 should be updated, got: %+v", result)
+		}
+	})
+
+	if _, err := c.UpsertRows(spreadsheetID, sheetTitle, "missing", [][]interface{}{{"a"}}); !errors.Is(err, ErrHeaderNotFound) {
+		t.Errorf("ErrHeaderNotFound expected, got: %v", err)
+	}
 }
 
 /*
@@ -124,3 +201,45 @@ func createNewSpreadsheet(t *testing.T) string {
 	}
 	return ssID
 }
+
+func TestCopyingSheets(t *testing.T) {
+	srcSpreadsheetID := createNewSpreadsheet(t)
+	dstSpreadsheetID := createNewSpreadsheet(t)
+	c := newTestClient(t)
+
+	templateTitle := "Template"
+	if err := c.RecreateSheet(srcSpreadsheetID, templateTitle); err != nil {
+		t.Fatal(err)
+	}
+	table := NewTable(1, 1)
+	table.PutValue(0, 0, "Template")
+	if err := c.WriteTable(srcSpreadsheetID, templateTitle, table); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("DuplicateSheet", func(t *testing.T) {
+		if err := c.DuplicateSheet(srcSpreadsheetID, templateTitle, "Duplicated", 0); err != nil {
+			t.Fatal(err)
+		}
+		titles, err := c.SheetTitles(srcSpreadsheetID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if titles[0] != "Duplicated" {
+			t.Errorf("First sheet should be Duplicated, got: %v", titles)
+		}
+	})
+
+	t.Run("CopySheetTo", func(t *testing.T) {
+		if err := c.CopySheetTo(srcSpreadsheetID, templateTitle, dstSpreadsheetID, "Customer"); err != nil {
+			t.Fatal(err)
+		}
+		copied, err := c.ReadTable(dstSpreadsheetID, "Customer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if copied.GetValue(0, 0) != "Template" {
+			t.Errorf("Template expected, got: %v", copied.GetValue(0, 0))
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package herschel
+
+import (
+	"image/color"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func toSheetsColor(c color.Color) *sheets.Color {
+	r, g, b, a := c.RGBA()
+	return &sheets.Color{Alpha: float64(a) / 65536, Blue: float64(b) / 65536, Green: float64(g) / 65536, Red: float64(r) / 65536}
+}
+
+func fromSheetsColor(c *sheets.Color) color.Color {
+	// Omitted alpha means a solid color.
+	alpha := c.Alpha
+	if alpha == 0 {
+		alpha = 1
+	}
+	return color.RGBA64{R: toColorComponent(c.Red), G: toColorComponent(c.Green), B: toColorComponent(c.Blue), A: toColorComponent(alpha)}
+}
+
+func toColorComponent(f float64) uint16 {
+	v := f * 65536
+	if v > 65535 {
+		return 65535
+	}
+	if v < 0 {
+		return 0
+	}
+	return uint16(v)
+}
@@ -0,0 +1,35 @@
+package herschel
+
+import (
+	"image/color"
+	"testing"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestSheetsColorConversion(t *testing.T) {
+	tests := []struct {
+		name string
+		c    color.Color
+	}{
+		{"Black", color.Black},
+		{"White", color.White},
+		{"Red", color.RGBA{255, 0, 0, 255}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r1, g1, b1, a1 := tt.c.RGBA()
+			r2, g2, b2, a2 := fromSheetsColor(toSheetsColor(tt.c)).RGBA()
+			if r1>>8 != r2>>8 || g1>>8 != g2>>8 || b1>>8 != b2>>8 || a1>>8 != a2>>8 {
+				t.Errorf("Color should survive conversion: %v -> %v", []uint32{r1, g1, b1, a1}, []uint32{r2, g2, b2, a2})
+			}
+		})
+	}
+
+	t.Run("OmittedAlpha", func(t *testing.T) {
+		_, _, _, a := fromSheetsColor(&sheets.Color{Red: 1}).RGBA()
+		if a != 65535 {
+			t.Errorf("Omitted alpha should be solid, got: %d", a)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package herschel
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+)
+
+// Errors returned by Client and Table methods. Use errors.Is to check them.
+var (
+	ErrSheetNotFound         = errors.New("sheet not found")
+	ErrSpreadsheetNotFound   = errors.New("spreadsheet not found")
+	ErrNotFound              = errors.New("requested entity not found")
+	ErrPermissionDenied      = errors.New("permission denied")
+	ErrQuotaExceeded         = errors.New("quota exceeded")
+	ErrInvalidRange          = errors.New("invalid range")
+	ErrServiceNotInitialized = errors.New("service not initialized")
+	ErrOutOfRange            = errors.New("cell out of range")
+	ErrHeaderNotFound        = errors.New("header not found")
+	ErrConflict              = errors.New("sheet was changed after it was read")
+)
+
+// APIError is an error returned by the spreadsheet api, classified by Kind.
+// The underlying *googleapi.Error can be retrieved with errors.As.
+type APIError struct {
+	// Kind is one of ErrSheetNotFound, ErrSpreadsheetNotFound, ErrNotFound, ErrPermissionDenied, ErrQuotaExceeded or ErrInvalidRange.
+	Kind error
+	Err  error
+}
+
+func (e *APIError) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of error.
+func (e *APIError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// wrapAPIError classifies an error returned by the spreadsheet api. Unknown errors are returned as is.
+func wrapAPIError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	if kind := apiErrorKind(apiErr); kind != nil {
+		return &APIError{Kind: kind, Err: err}
+	}
+	return err
+}
+
+func apiErrorKind(e *googleapi.Error) error {
+	for _, item := range e.Errors {
+		switch item.Reason {
+		case "rateLimitExceeded", "userRateLimitExceeded", "quotaExceeded":
+			return ErrQuotaExceeded
+		}
+	}
+
+	switch e.Code {
+	case http.StatusNotFound:
+		// Spreadsheets are the only entities looked up by path, and the api reports them missing with this message.
+		if strings.Contains(e.Message, "Requested entity was not found") {
+			return ErrSpreadsheetNotFound
+		}
+		return ErrNotFound
+	case http.StatusForbidden:
+		return ErrPermissionDenied
+	case http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case http.StatusBadRequest:
+		if strings.Contains(e.Message, "Unable to parse range") || strings.Contains(e.Message, "exceeds grid limits") {
+			return ErrInvalidRange
+		}
+	}
+	return nil
+}
+
+// valuesAPIError classifies an error returned by the values api for a range in sheet.
+// The api reports a missing sheet as a range which can not be parsed, so it is reported as ErrSheetNotFound when the sheet does not exist.
+func (client Client) valuesAPIError(spreadsheetID string, sheetTitle string, err error) error {
+	err = wrapAPIError(err)
+	if !errors.Is(err, ErrInvalidRange) {
+		return err
+	}
+	if _, exists, lookupErr := getSheetID(client, spreadsheetID, sheetTitle); lookupErr != nil || exists {
+		return err
+	}
+	return asSheetNotFound(err)
+}
+
+// asSheetNotFound returns err of the api reclassified as ErrSheetNotFound.
+func asSheetNotFound(err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	return &APIError{Kind: ErrSheetNotFound, Err: apiErr.Err}
+}
+
+func sheetNotFoundError(sheetTitle string) error {
+	return errors.Wrapf(ErrSheetNotFound, "sheet with title %s", sheetTitle)
+}
@@ -0,0 +1,162 @@
+package herschel
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestWrapAPIError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *googleapi.Error
+		want error
+	}{
+		{"SpreadsheetNotFound", &googleapi.Error{Code: http.StatusNotFound, Message: "Requested entity was not found."}, ErrSpreadsheetNotFound},
+		{"NotFound", &googleapi.Error{Code: http.StatusNotFound, Message: "No developer metadata with ID: 1"}, ErrNotFound},
+		{"Forbidden", &googleapi.Error{Code: http.StatusForbidden}, ErrPermissionDenied},
+		{"TooManyRequests", &googleapi.Error{Code: http.StatusTooManyRequests}, ErrQuotaExceeded},
+		{"RateLimitReason", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}, ErrQuotaExceeded},
+		{"UnableToParseRange", &googleapi.Error{Code: http.StatusBadRequest, Message: "Unable to parse range: Foo"}, ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapAPIError(errors.WithStack(tt.err))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("errors.Is(%v, %v) should be true", err, tt.want)
+			}
+
+			var apiErr *googleapi.Error
+			if !errors.As(err, &apiErr) || apiErr.Code != tt.err.Code {
+				t.Errorf("errors.As should find the underlying googleapi.Error in %v", err)
+			}
+
+			var e *APIError
+			if !errors.As(err, &e) || e.Kind != tt.want {
+				t.Errorf("errors.As should find APIError with kind %v in %v", tt.want, err)
+			}
+		})
+	}
+
+	t.Run("Unclassified", func(t *testing.T) {
+		orig := &googleapi.Error{Code: http.StatusInternalServerError}
+		if err := wrapAPIError(orig); err != orig {
+			t.Errorf("Unclassified error should be returned as is, got: %v", err)
+		}
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		if err := wrapAPIError(nil); err != nil {
+			t.Errorf("nil expected, got: %v", err)
+		}
+	})
+}
+
+func TestAsSheetNotFound(t *testing.T) {
+	orig := &googleapi.Error{Code: http.StatusBadRequest, Message: "Unable to parse range: NoSuchSheet"}
+	err := asSheetNotFound(wrapAPIError(orig))
+	if !errors.Is(err, ErrSheetNotFound) || errors.Is(err, ErrInvalidRange) {
+		t.Errorf("Only ErrSheetNotFound expected, got: %v", err)
+	}
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr != orig {
+		t.Errorf("errors.As should find the underlying googleapi.Error in %v", err)
+	}
+}
+
+func TestServiceNotInitialized(t *testing.T) {
+	c := Client{}
+
+	if _, err := c.Read("spreadsheetID", "Sheet1"); !errors.Is(err, ErrServiceNotInitialized) {
+		t.Errorf("ErrServiceNotInitialized expected, got: %v", err)
+	}
+	if err := c.WriteTable("spreadsheetID", "Sheet1", NewTable(1, 1)); !errors.Is(err, ErrServiceNotInitialized) {
+		t.Errorf("ErrServiceNotInitialized expected, got: %v", err)
+	}
+	if err := c.DeleteSheet("spreadsheetID", "Sheet1"); !errors.Is(err, ErrServiceNotInitialized) {
+		t.Errorf("ErrServiceNotInitialized expected, got: %v", err)
+	}
+}
+
+func TestSheetNotFoundError(t *testing.T) {
+	spreadsheetID := createNewSpreadsheet(t)
+	c := newTestClient(t)
+
+	if err := c.UpdateSheetGridLimits(spreadsheetID, "NoSuchSheet", 10, 10); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected, got: %v", err)
+	}
+	if _, err := c.Read(spreadsheetID, "NoSuchSheet"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on read, got: %v", err)
+	}
+	if _, err := c.ReadTable(spreadsheetID, "NoSuchSheet"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on read table, got: %v", err)
+	}
+	if err := c.Write(spreadsheetID, "NoSuchSheet", [][]interface{}{{"a"}}); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on write, got: %v", err)
+	}
+	if err := c.WriteTableAt(spreadsheetID, "NoSuchSheet", NewTable(1, 1), 1, 1); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on write at offset, got: %v", err)
+	}
+	if err := c.ClearSheetValues(spreadsheetID, "NoSuchSheet"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on clear, got: %v", err)
+	}
+	if _, err := c.SyncTable(spreadsheetID, "NoSuchSheet", NewTable(1, 1)); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on sync, got: %v", err)
+	}
+	if _, err := c.UpsertRows(spreadsheetID, "NoSuchSheet", "id", [][]interface{}{{"a"}}); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on upsert, got: %v", err)
+	}
+	it := c.RowIterator(spreadsheetID, "NoSuchSheet", 10)
+	if it.Next() || !errors.Is(it.Err(), ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on iteration, got: %v", it.Err())
+	}
+	if _, err := c.Read("NoSuchSpreadsheet", "Sheet1"); !errors.Is(err, ErrSpreadsheetNotFound) {
+		t.Errorf("ErrSpreadsheetNotFound expected, got: %v", err)
+	}
+}
+
+func TestSheetNotFoundOnValues(t *testing.T) {
+	// The api reports ranges of missing sheets as unparsable, and lists the existing sheets.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet && r.URL.Query().Get("fields") == "sheets.properties(sheetId,title)" {
+			fmt.Fprint(w, `{"sheets": [{"properties": {"sheetId": 0, "title": "Sheet1"}}]}`)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error": {"code": 400, "message": "Unable to parse range: NoSuchSheet"}}`)
+	}))
+	defer server.Close()
+
+	service, err := sheets.New(server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.BasePath = server.URL + "/"
+	c := &Client{service: service, cache: newMetadataCache()}
+
+	if _, err := c.Read("spreadsheetID", "NoSuchSheet"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on read, got: %v", err)
+	}
+	if err := c.Write("spreadsheetID", "NoSuchSheet", [][]interface{}{{"a"}}); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on write, got: %v", err)
+	}
+	if _, err := c.SyncTable("spreadsheetID", "NoSuchSheet", NewTable(1, 1)); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on reading grid, got: %v", err)
+	}
+	if _, err := c.UpsertRows("spreadsheetID", "NoSuchSheet", "id", [][]interface{}{{"a"}}); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on upsert, got: %v", err)
+	}
+	data := []*sheets.ValueRange{{Range: cellRange("NoSuchSheet", 0, 0, 1, 1), Values: [][]interface{}{{"a"}}}}
+	if err := c.batchUpdateValues("spreadsheetID", "NoSuchSheet", data); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected on batch update, got: %v", err)
+	}
+	if _, err := c.Read("spreadsheetID", "Sheet1"); !errors.Is(err, ErrInvalidRange) || errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrInvalidRange expected for existing sheet, got: %v", err)
+	}
+}
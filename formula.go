@@ -0,0 +1,468 @@
+package herschel
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Formula is a formula of cell such as "=SUM(B2:B10)", written to spreadsheet as entered by user.
+// References to cells of the table are shifted when columns are inserted or removed, tables are appended or sliced,
+// and the table is written at an offset. Ranges shrink to the remaining cells, and references which no longer exist become #REF!.
+// References to other sheets such as Sheet2!A1 are kept as is.
+type Formula string
+
+type formulaTokenKind int
+
+const (
+	formulaSpace formulaTokenKind = iota
+	formulaNumber
+	formulaString
+	formulaBool
+	formulaRef
+	formulaFunction
+	formulaName
+	formulaOperator
+	formulaOpenParen
+	formulaCloseParen
+	formulaSeparator
+	formulaErrorValue
+)
+
+type formulaToken struct {
+	kind formulaTokenKind
+	// text is the text of token as written in formula.
+	text string
+	// ref is set for formulaRef.
+	ref formulaReference
+}
+
+// formulaReference is a reference to a cell, a range, whole columns or whole rows.
+type formulaReference struct {
+	// sheet is the sheet part as written such as 'Sheet 1'. It is empty for references to the table itself.
+	sheet string
+	start refPoint
+	end   refPoint
+	// isRange is true for ranges such as A1:B2, A:B and 1:2, and open-ended ranges such as B2:B and A2:2.
+	// The end of open-ended range has no row or no column, which extends to the last row or column.
+	isRange bool
+}
+
+// refPoint is an end of reference. Row is -1 for whole columns, and col is -1 for whole rows.
+type refPoint struct {
+	row    int
+	col    int
+	rowAbs bool
+	colAbs bool
+}
+
+// formulaErrorValues are error values which may appear in formulas.
+var formulaErrorValues = []string{"#NULL!", "#DIV/0!", "#VALUE!", "#REF!", "#NAME?", "#NUM!", "#N/A", "#ERROR!"}
+
+// tokenizeFormula splits formula into tokens. The leading = is not a token.
+func tokenizeFormula(formula string) ([]formulaToken, error) {
+	s := strings.TrimPrefix(formula, "=")
+	tokens := []formulaToken{}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			end := i
+			for end < len(s) && strings.IndexByte(" \t\n\r", s[end]) >= 0 {
+				end++
+			}
+			tokens = append(tokens, formulaToken{kind: formulaSpace, text: s[i:end]})
+			i = end
+		case c == '"':
+			end := i + 1
+			for ; end < len(s); end++ {
+				if s[end] == '"' {
+					if end+1 < len(s) && s[end+1] == '"' {
+						end++
+						continue
+					}
+					break
+				}
+			}
+			if end >= len(s) {
+				return nil, errors.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, formulaToken{kind: formulaString, text: s[i : end+1]})
+			i = end + 1
+		case c == '#':
+			matched := ""
+			for _, e := range formulaErrorValues {
+				if strings.HasPrefix(strings.ToUpper(s[i:]), e) {
+					matched = s[i : i+len(e)]
+					break
+				}
+			}
+			if len(matched) == 0 {
+				return nil, errors.Errorf("unknown error value at %d", i)
+			}
+			tokens = append(tokens, formulaToken{kind: formulaErrorValue, text: matched})
+			i += len(matched)
+		case c == '(':
+			tokens = append(tokens, formulaToken{kind: formulaOpenParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, formulaToken{kind: formulaCloseParen, text: ")"})
+			i++
+		case c == ',' || c == ';':
+			tokens = append(tokens, formulaToken{kind: formulaSeparator, text: s[i : i+1]})
+			i++
+		case c == '<' || c == '>':
+			n := 1
+			if i+1 < len(s) && (s[i+1] == '=' || c == '<' && s[i+1] == '>') {
+				n = 2
+			}
+			tokens = append(tokens, formulaToken{kind: formulaOperator, text: s[i : i+n]})
+			i += n
+		case strings.IndexByte("+-*/^&=%", c) >= 0:
+			tokens = append(tokens, formulaToken{kind: formulaOperator, text: s[i : i+1]})
+			i++
+		default:
+			token, n, err := readFormulaOperand(s[i:])
+			if err != nil {
+				return nil, errors.Wrapf(err, "at %d", i)
+			}
+			tokens = append(tokens, token)
+			i += n
+		}
+	}
+	return tokens, nil
+}
+
+// readFormulaOperand reads a reference, a number, a function name, a boolean or a name at the beginning of s.
+func readFormulaOperand(s string) (formulaToken, int, error) {
+	if ref, n, ok := readFormulaReference(s); ok {
+		return formulaToken{kind: formulaRef, text: s[:n], ref: ref}, n, nil
+	}
+
+	c := s[0]
+	if c >= '0' && c <= '9' || c == '.' {
+		n := 0
+		for n < len(s) && (s[n] >= '0' && s[n] <= '9' || s[n] == '.') {
+			n++
+		}
+		if n < len(s) && (s[n] == 'e' || s[n] == 'E') {
+			m := n + 1
+			if m < len(s) && (s[m] == '+' || s[m] == '-') {
+				m++
+			}
+			if m < len(s) && s[m] >= '0' && s[m] <= '9' {
+				for m < len(s) && s[m] >= '0' && s[m] <= '9' {
+					m++
+				}
+				n = m
+			}
+		}
+		if _, err := strconv.ParseFloat(s[:n], 64); err != nil {
+			return formulaToken{}, 0, errors.Errorf("invalid number %s", s[:n])
+		}
+		return formulaToken{kind: formulaNumber, text: s[:n]}, n, nil
+	}
+
+	n := 0
+	for n < len(s) && isFormulaNameByte(s[n]) {
+		n++
+	}
+	if n == 0 {
+		return formulaToken{}, 0, errors.Errorf("unexpected character %q", c)
+	}
+	name := s[:n]
+	switch {
+	case n < len(s) && s[n] == '(':
+		return formulaToken{kind: formulaFunction, text: name}, n, nil
+	case strings.EqualFold(name, "TRUE") || strings.EqualFold(name, "FALSE"):
+		return formulaToken{kind: formulaBool, text: name}, n, nil
+	}
+	return formulaToken{kind: formulaName, text: name}, n, nil
+}
+
+func isFormulaNameByte(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '$'
+}
+
+// readFormulaReference reads a reference such as A1, $B$2, A1:C3, A:C, 1:3, B2:B or 'Sheet 1'!A1 at the beginning of s.
+func readFormulaReference(s string) (formulaReference, int, bool) {
+	ref := formulaReference{}
+	i := 0
+	if s[0] == '\'' {
+		end := 1
+		for ; end < len(s); end++ {
+			if s[end] == '\'' {
+				if end+1 < len(s) && s[end+1] == '\'' {
+					end++
+					continue
+				}
+				break
+			}
+		}
+		if end+1 >= len(s) || s[end+1] != '!' {
+			return ref, 0, false
+		}
+		ref.sheet = s[:end+1]
+		i = end + 2
+	} else {
+		n := 0
+		for n < len(s) && isFormulaNameByte(s[n]) {
+			n++
+		}
+		if n > 0 && n < len(s) && s[n] == '!' {
+			ref.sheet = s[:n]
+			i = n + 1
+		}
+	}
+
+	start, n := readRefPoint(s[i:])
+	if n == 0 {
+		return ref, 0, false
+	}
+	ref.start, ref.end = start, start
+	i += n
+	if i < len(s) && s[i] == ':' {
+		end, m := readRefPoint(s[i+1:])
+		sameKind := (end.row < 0) == (start.row < 0) && (end.col < 0) == (start.col < 0)
+		openEnded := start.row >= 0 && start.col >= 0
+		if j := i + 1 + m; m > 0 && (sameKind || openEnded) && (j == len(s) || !isFormulaNameByte(s[j]) && s[j] != '(' && s[j] != '!') {
+			ref.end = end
+			ref.isRange = true
+			i = j
+		}
+	}
+	// Whole columns and rows need the other end, and a reference is not followed by a name or a call.
+	if !ref.isRange && (start.row < 0 || start.col < 0) {
+		return ref, 0, false
+	}
+	if i < len(s) && (isFormulaNameByte(s[i]) || s[i] == '(' || s[i] == '!') {
+		return ref, 0, false
+	}
+	return ref, i, true
+}
+
+// readRefPoint reads an end of reference such as $A$1, A or 1. It returns zero length when s does not begin with it.
+func readRefPoint(s string) (refPoint, int) {
+	p := refPoint{row: -1, col: -1}
+	i := 0
+	if i < len(s) && s[i] == '$' {
+		p.colAbs = true
+		i++
+	}
+	letters := i
+	col := 0
+	for i < len(s) && i-letters < 3 && (s[i] >= 'A' && s[i] <= 'Z' || s[i] >= 'a' && s[i] <= 'z') {
+		col = col*26 + int(s[i]|0x20-'a'+1)
+		i++
+	}
+	if i > letters {
+		p.col = col - 1
+	} else if p.colAbs {
+		// $ of row such as $1
+		p.colAbs = false
+		p.rowAbs = true
+	}
+
+	if i < len(s) && s[i] == '$' && !p.rowAbs {
+		p.rowAbs = true
+		i++
+	}
+	digits := i
+	row := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' && row < 1e8 {
+		row = row*10 + int(s[i]-'0')
+		i++
+	}
+	if i > digits {
+		if row == 0 {
+			return p, 0
+		}
+		p.row = row - 1
+	} else if p.rowAbs && p.col >= 0 {
+		return p, 0
+	}
+	if p.row < 0 && p.col < 0 {
+		return p, 0
+	}
+	return p, i
+}
+
+func (p refPoint) String() string {
+	s := ""
+	if p.col >= 0 {
+		if p.colAbs {
+			s += "$"
+		}
+		s += columnName(p.col)
+	}
+	if p.row >= 0 {
+		if p.rowAbs {
+			s += "$"
+		}
+		s += strconv.Itoa(p.row + 1)
+	}
+	return s
+}
+
+func (r formulaReference) String() string {
+	s := r.sheet
+	if len(s) > 0 {
+		s += "!"
+	}
+	s += r.start.String()
+	if r.isRange {
+		s += ":" + r.end.String()
+	}
+	return s
+}
+
+// indexMap maps index of row or column to the new index. It returns false when the row or column is removed.
+type indexMap func(index int) (int, bool)
+
+// offsetMap moves indices by delta. Indices moved before the first row or column are removed.
+func offsetMap(delta int) indexMap {
+	return func(index int) (int, bool) {
+		return index + delta, index+delta >= 0
+	}
+}
+
+// insertMap moves indices from index by count, as inserting rows or columns at index.
+func insertMap(index int, count int) indexMap {
+	return func(i int) (int, bool) {
+		if i >= index {
+			return i + count, true
+		}
+		return i, true
+	}
+}
+
+// removeMap removes index and moves the following indices back.
+func removeMap(index int) indexMap {
+	return func(i int) (int, bool) {
+		switch {
+		case i == index:
+			return 0, false
+		case i > index:
+			return i - 1, true
+		}
+		return i, true
+	}
+}
+
+// remap returns the reference with rows and columns mapped. A range shrinks to the remaining rows and columns.
+// It returns false when the referenced cells are all removed.
+func (r formulaReference) remap(rowMap indexMap, colMap indexMap) (formulaReference, bool) {
+	if len(r.sheet) > 0 {
+		return r, true
+	}
+	var ok bool
+	switch {
+	case rowMap == nil || r.start.row < 0:
+	case r.end.row < 0:
+		if r.start.row, ok = remapOpenSpan(r.start.row, rowMap, maxSheetRows); !ok {
+			return r, false
+		}
+	default:
+		if r.start.row, r.end.row, ok = remapSpan(r.start.row, r.end.row, rowMap); !ok {
+			return r, false
+		}
+	}
+	switch {
+	case colMap == nil || r.start.col < 0:
+	case r.end.col < 0:
+		if r.start.col, ok = remapOpenSpan(r.start.col, colMap, maxSheetCols); !ok {
+			return r, false
+		}
+	default:
+		if r.start.col, r.end.col, ok = remapSpan(r.start.col, r.end.col, colMap); !ok {
+			return r, false
+		}
+	}
+	return r, true
+}
+
+// remapOpenSpan maps the start of span which extends to limit. Removed start moves to the nearest following index.
+func remapOpenSpan(start int, m indexMap, limit int) (int, bool) {
+	for i := start; i < limit; i++ {
+		if mapped, ok := m(i); ok {
+			return mapped, true
+		}
+	}
+	return 0, false
+}
+
+// remapSpan maps both ends of span from start to end. Removed ends move inward to the nearest remaining index.
+func remapSpan(start int, end int, m indexMap) (int, int, bool) {
+	if start > end {
+		start, end = end, start
+	}
+	newStart, newEnd := 0, 0
+	found := false
+	for i := start; i <= end; i++ {
+		if mapped, ok := m(i); ok {
+			newStart, found = mapped, true
+			break
+		}
+	}
+	if !found {
+		return 0, 0, false
+	}
+	for i := end; i >= start; i-- {
+		if mapped, ok := m(i); ok {
+			newEnd = mapped
+			break
+		}
+	}
+	return newStart, newEnd, true
+}
+
+// shifted returns the formula with references to the table mapped.
+// The formula is logged and returned as is when it can not be parsed, since its references may be wrong.
+func (f Formula) shifted(rowMap indexMap, colMap indexMap) Formula {
+	tokens, err := tokenizeFormula(string(f))
+	if err != nil {
+		log.Printf("References of formula %s are not shifted: %v\n", f, err)
+		return f
+	}
+	var b strings.Builder
+	if strings.HasPrefix(string(f), "=") {
+		b.WriteString("=")
+	}
+	for _, token := range tokens {
+		if token.kind != formulaRef {
+			b.WriteString(token.text)
+			continue
+		}
+		if ref, ok := token.ref.remap(rowMap, colMap); ok {
+			b.WriteString(ref.String())
+		} else {
+			b.WriteString("#REF!")
+		}
+	}
+	return Formula(b.String())
+}
+
+// shiftFormulas maps references of formulas in cells from (rowStart, colStart) to (rowEnd, colEnd) exclusive.
+func (t *Table) shiftFormulas(rowStart int, rowEnd int, colStart int, colEnd int, rowMap indexMap, colMap indexMap) {
+	for row := rowStart; row < rowEnd && row < len(t.cells); row++ {
+		cells := t.cells[row]
+		for col := colStart; col < colEnd && col < len(cells); col++ {
+			if f, ok := cells[col].(Formula); ok {
+				cells[col] = f.shifted(rowMap, colMap)
+			}
+		}
+	}
+}
+
+// withOffset returns new instance of table with cells moved by rows and cols, and references of formulas shifted.
+func (t *Table) withOffset(rows int, cols int) *Table {
+	moved := NewTable(t.rows+rows, t.cols+cols)
+	moved.copyColumnWidthsFromTable(cols, t, 0, t.cols)
+	for row := 0; row < t.rows; row++ {
+		moved.copyRowFromTable(row+rows, cols, t, row, 0, t.cols)
+	}
+	moved.shiftFormulas(rows, rows+t.rows, cols, cols+t.cols, offsetMap(rows), offsetMap(cols))
+	return moved
+}
@@ -0,0 +1,608 @@
+package herschel
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FormulaError is an error value resulting from formula such as #DIV/0!.
+type FormulaError string
+
+// Error values of formulas. Circular references result in FormulaErrorRef as spreadsheet does.
+const (
+	FormulaErrorDivByZero FormulaError = "#DIV/0!"
+	FormulaErrorRef       FormulaError = "#REF!"
+	FormulaErrorValue     FormulaError = "#VALUE!"
+	FormulaErrorName      FormulaError = "#NAME?"
+	FormulaErrorNA        FormulaError = "#N/A"
+	FormulaErrorNum       FormulaError = "#NUM!"
+	FormulaErrorNull      FormulaError = "#NULL!"
+	// FormulaErrorParse is the result of formulas which can not be parsed.
+	FormulaErrorParse FormulaError = "#ERROR!"
+)
+
+func (e FormulaError) Error() string {
+	return string(e)
+}
+
+// Evaluate returns result of the formula at cell, computed locally. Values of cells without formulas are returned as is.
+// Results are float64, string, bool, FormulaError or nil for empty results.
+// References to other sheets result in FormulaErrorRef.
+func (t *Table) Evaluate(row int, col int) interface{} {
+	v := t.GetValue(row, col)
+	if _, ok := v.(Formula); !ok {
+		return v
+	}
+	return newFormulaEvaluator(t).cell(row, col)
+}
+
+// EvaluateFormula returns result of formula computed with values of table.
+func (t *Table) EvaluateFormula(f Formula) interface{} {
+	return newFormulaEvaluator(t).evaluate(f)
+}
+
+// Evaluated returns new instance of table with formulas replaced by their results.
+func (t *Table) Evaluated() *Table {
+	e := newFormulaEvaluator(t)
+	evaluated := NewTable(t.rows, t.cols)
+	evaluated.copyPropertiesFromTable(t)
+	for row := 0; row < t.rows; row++ {
+		evaluated.copyRowFromTable(row, 0, t, row, 0, t.cols)
+		for col := 0; col < t.cols; col++ {
+			if _, ok := t.GetValue(row, col).(Formula); ok {
+				evaluated.PutValue(row, col, e.cell(row, col))
+			}
+		}
+	}
+	return evaluated
+}
+
+type formulaEvaluator struct {
+	table *Table
+	// results caches results of formulas in cells.
+	results map[[2]int]interface{}
+	// evaluating holds cells being evaluated to detect circular references.
+	evaluating map[[2]int]bool
+}
+
+func newFormulaEvaluator(t *Table) *formulaEvaluator {
+	return &formulaEvaluator{
+		table:      t,
+		results:    map[[2]int]interface{}{},
+		evaluating: map[[2]int]bool{},
+	}
+}
+
+func (e *formulaEvaluator) evaluate(f Formula) interface{} {
+	node, err := parseFormula(f)
+	if err != nil {
+		return FormulaErrorParse
+	}
+	v := scalarValue(node.eval(e))
+	if n, ok := v.(float64); ok && (math.IsNaN(n) || math.IsInf(n, 0)) {
+		return FormulaErrorNum
+	}
+	return v
+}
+
+// cell returns value of cell, evaluating formula.
+func (e *formulaEvaluator) cell(row int, col int) interface{} {
+	v := e.table.GetValue(row, col)
+	f, ok := v.(Formula)
+	if !ok {
+		return formulaValue(v)
+	}
+
+	key := [2]int{row, col}
+	if result, ok := e.results[key]; ok {
+		return result
+	}
+	if e.evaluating[key] {
+		return FormulaErrorRef
+	}
+	e.evaluating[key] = true
+	result := e.evaluate(f)
+	delete(e.evaluating, key)
+	e.results[key] = result
+	return result
+}
+
+// formulaValue converts value of cell to float64, string, bool, FormulaError or nil.
+func formulaValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case nil, bool, FormulaError:
+		return v
+	case string:
+		if len(value) == 0 {
+			return nil
+		}
+		return value
+	case time.Time:
+		return excelSerial(value)
+	}
+	if f, ok := toFloat64(v); ok {
+		return f
+	}
+	return userEnteredString(v)
+}
+
+// formulaRange is values of a range in formula.
+type formulaRange [][]interface{}
+
+func (r formulaRange) values() []interface{} {
+	values := []interface{}{}
+	for _, row := range r {
+		values = append(values, row...)
+	}
+	return values
+}
+
+// scalarValue returns value of a single cell range, or the value itself when it is not a range.
+func scalarValue(v interface{}) interface{} {
+	r, ok := v.(formulaRange)
+	if !ok {
+		return v
+	}
+	if len(r) == 1 && len(r[0]) == 1 {
+		return r[0][0]
+	}
+	return FormulaErrorValue
+}
+
+func toFormulaNumber(v interface{}) (float64, FormulaError) {
+	switch value := scalarValue(v).(type) {
+	case nil:
+		return 0, ""
+	case float64:
+		return value, ""
+	case bool:
+		if value {
+			return 1, ""
+		}
+		return 0, ""
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return f, ""
+		}
+		return 0, FormulaErrorValue
+	case FormulaError:
+		return 0, value
+	}
+	return 0, FormulaErrorValue
+}
+
+func toFormulaText(v interface{}) (string, FormulaError) {
+	switch value := scalarValue(v).(type) {
+	case nil:
+		return "", ""
+	case float64:
+		return formatGeneral(value), ""
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(value)), ""
+	case string:
+		return value, ""
+	case FormulaError:
+		return "", value
+	}
+	return "", FormulaErrorValue
+}
+
+func toFormulaBool(v interface{}) (bool, FormulaError) {
+	switch value := scalarValue(v).(type) {
+	case nil:
+		return false, ""
+	case bool:
+		return value, ""
+	case float64:
+		return value != 0, ""
+	case string:
+		switch strings.ToUpper(value) {
+		case "TRUE":
+			return true, ""
+		case "FALSE":
+			return false, ""
+		}
+		return false, FormulaErrorValue
+	case FormulaError:
+		return false, value
+	}
+	return false, FormulaErrorValue
+}
+
+// compareFormulaValues compares values as spreadsheet does. Numbers are less than text, and text is less than booleans.
+// Text is compared ignoring case, and empty is compared as zero, empty text or FALSE.
+func compareFormulaValues(a interface{}, b interface{}) int {
+	rank := func(v interface{}) int {
+		switch v.(type) {
+		case float64:
+			return 0
+		case string:
+			return 1
+		case bool:
+			return 2
+		}
+		return -1
+	}
+	if a == nil {
+		a = emptyValueLike(b)
+	}
+	if b == nil {
+		b = emptyValueLike(a)
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+
+	switch x := a.(type) {
+	case float64:
+		y := b.(float64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	case string:
+		return strings.Compare(strings.ToLower(x), strings.ToLower(b.(string)))
+	case bool:
+		switch y := b.(bool); {
+		case !x && y:
+			return -1
+		case x && !y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func emptyValueLike(v interface{}) interface{} {
+	switch v.(type) {
+	case string:
+		return ""
+	case bool:
+		return false
+	}
+	return float64(0)
+}
+
+type formulaNode interface {
+	eval(e *formulaEvaluator) interface{}
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n literalNode) eval(e *formulaEvaluator) interface{} {
+	return n.value
+}
+
+type refNode struct {
+	ref formulaReference
+}
+
+// eval returns formulaRange of the referenced cells. Ranges are limited to the table, keeping at least their first cell.
+func (n refNode) eval(e *formulaEvaluator) interface{} {
+	if len(n.ref.sheet) > 0 {
+		return FormulaErrorRef
+	}
+	rowStart, rowEnd := n.ref.start.row, n.ref.end.row
+	colStart, colEnd := n.ref.start.col, n.ref.end.col
+	if rowStart < 0 {
+		rowStart, rowEnd = 0, e.table.rows-1
+	} else {
+		rowStart, rowEnd = limitSpan(rowStart, rowEnd, e.table.rows)
+	}
+	if colStart < 0 {
+		colStart, colEnd = 0, e.table.cols-1
+	} else {
+		colStart, colEnd = limitSpan(colStart, colEnd, e.table.cols)
+	}
+
+	r := make(formulaRange, 0, rowEnd-rowStart+1)
+	for row := rowStart; row <= rowEnd; row++ {
+		values := make([]interface{}, 0, colEnd-colStart+1)
+		for col := colStart; col <= colEnd; col++ {
+			values = append(values, e.cell(row, col))
+		}
+		r = append(r, values)
+	}
+	return r
+}
+
+// limitSpan orders span from start to end, and limits its end to size. Negative end is the open end of a range such as B2:B.
+func limitSpan(start int, end int, size int) (int, int) {
+	if end >= 0 && start > end {
+		start, end = end, start
+	}
+	if end < 0 || end >= size {
+		end = size - 1
+	}
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+type unaryNode struct {
+	op string
+	x  formulaNode
+}
+
+func (n unaryNode) eval(e *formulaEvaluator) interface{} {
+	x, err := toFormulaNumber(n.x.eval(e))
+	if err != "" {
+		return err
+	}
+	switch n.op {
+	case "-":
+		return -x
+	case "%":
+		return x / 100
+	}
+	return x
+}
+
+type binaryNode struct {
+	op   string
+	l, r formulaNode
+}
+
+func (n binaryNode) eval(e *formulaEvaluator) interface{} {
+	l, r := scalarValue(n.l.eval(e)), scalarValue(n.r.eval(e))
+	if err, ok := l.(FormulaError); ok {
+		return err
+	}
+	if err, ok := r.(FormulaError); ok {
+		return err
+	}
+
+	switch n.op {
+	case "&":
+		a, _ := toFormulaText(l)
+		b, _ := toFormulaText(r)
+		return a + b
+	case "=":
+		return compareFormulaValues(l, r) == 0
+	case "<>":
+		return compareFormulaValues(l, r) != 0
+	case "<":
+		return compareFormulaValues(l, r) < 0
+	case ">":
+		return compareFormulaValues(l, r) > 0
+	case "<=":
+		return compareFormulaValues(l, r) <= 0
+	case ">=":
+		return compareFormulaValues(l, r) >= 0
+	}
+
+	a, err := toFormulaNumber(l)
+	if err != "" {
+		return err
+	}
+	b, err := toFormulaNumber(r)
+	if err != "" {
+		return err
+	}
+	switch n.op {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	case "/":
+		if b == 0 {
+			return FormulaErrorDivByZero
+		}
+		return a / b
+	case "^":
+		if p := math.Pow(a, b); !math.IsNaN(p) && !math.IsInf(p, 0) {
+			return p
+		}
+		return FormulaErrorNum
+	}
+	return FormulaErrorValue
+}
+
+type callNode struct {
+	name string
+	args []formulaNode
+}
+
+func (n callNode) eval(e *formulaEvaluator) interface{} {
+	f, ok := formulaFunctions[strings.ToUpper(n.name)]
+	if !ok {
+		return FormulaErrorName
+	}
+	return f(e, n.args)
+}
+
+// emptyNode is an omitted argument such as the second argument of IF(A1,,1).
+type emptyNode struct{}
+
+func (emptyNode) eval(e *formulaEvaluator) interface{} {
+	return nil
+}
+
+// formulaParser parses tokens of formula by precedence of operators: comparison, &, + -, * /, ^, unary - +, and %.
+type formulaParser struct {
+	tokens []formulaToken
+	pos    int
+}
+
+func parseFormula(f Formula) (formulaNode, error) {
+	tokens, err := tokenizeFormula(string(f))
+	if err != nil {
+		return nil, err
+	}
+	p := &formulaParser{}
+	for _, token := range tokens {
+		if token.kind != formulaSpace {
+			p.tokens = append(p.tokens, token)
+		}
+	}
+	if len(p.tokens) == 0 {
+		return nil, errors.New("empty formula")
+	}
+	node, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, errors.Errorf("unexpected %s", p.tokens[p.pos].text)
+	}
+	return node, nil
+}
+
+// operator returns operator at current position if it is one of ops.
+func (p *formulaParser) operator(ops ...string) (string, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != formulaOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if p.tokens[p.pos].text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// parseBinary parses left associative binary operators of ops, whose operands are parsed by next.
+func (p *formulaParser) parseBinary(next func() (formulaNode, error), ops ...string) (formulaNode, error) {
+	l, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.operator(ops...)
+		if !ok {
+			return l, nil
+		}
+		r, err := next()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: op, l: l, r: r}
+	}
+}
+
+func (p *formulaParser) parseComparison() (formulaNode, error) {
+	return p.parseBinary(p.parseConcatenation, "=", "<>", "<=", ">=", "<", ">")
+}
+
+func (p *formulaParser) parseConcatenation() (formulaNode, error) {
+	return p.parseBinary(p.parseAdditive, "&")
+}
+
+func (p *formulaParser) parseAdditive() (formulaNode, error) {
+	return p.parseBinary(p.parseMultiplicative, "+", "-")
+}
+
+func (p *formulaParser) parseMultiplicative() (formulaNode, error) {
+	return p.parseBinary(p.parsePower, "*", "/")
+}
+
+func (p *formulaParser) parsePower() (formulaNode, error) {
+	return p.parseBinary(p.parseUnary, "^")
+}
+
+func (p *formulaParser) parseUnary() (formulaNode, error) {
+	if op, ok := p.operator("-", "+"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: op, x: x}, nil
+	}
+
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.operator("%"); !ok {
+			return x, nil
+		}
+		x = unaryNode{op: "%", x: x}
+	}
+}
+
+func (p *formulaParser) parsePrimary() (formulaNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, errors.New("unexpected end of formula")
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch token.kind {
+	case formulaNumber:
+		f, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid number %s", token.text)
+		}
+		return literalNode{value: f}, nil
+	case formulaString:
+		return literalNode{value: strings.ReplaceAll(token.text[1:len(token.text)-1], `""`, `"`)}, nil
+	case formulaBool:
+		return literalNode{value: strings.EqualFold(token.text, "TRUE")}, nil
+	case formulaErrorValue:
+		return literalNode{value: FormulaError(strings.ToUpper(token.text))}, nil
+	case formulaRef:
+		return refNode{ref: token.ref}, nil
+	case formulaName:
+		return literalNode{value: FormulaErrorName}, nil
+	case formulaOpenParen:
+		node, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != formulaCloseParen {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return node, nil
+	case formulaFunction:
+		return p.parseCall(token.text)
+	}
+	return nil, errors.Errorf("unexpected %s", token.text)
+}
+
+func (p *formulaParser) parseCall(name string) (formulaNode, error) {
+	// Skip (
+	p.pos++
+	call := callNode{name: name}
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == formulaCloseParen {
+		p.pos++
+		return call, nil
+	}
+	for {
+		if p.pos < len(p.tokens) && (p.tokens[p.pos].kind == formulaSeparator || p.tokens[p.pos].kind == formulaCloseParen) {
+			call.args = append(call.args, emptyNode{})
+		} else {
+			arg, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+		}
+
+		if p.pos >= len(p.tokens) {
+			return nil, errors.Errorf("missing ) of %s", name)
+		}
+		token := p.tokens[p.pos]
+		p.pos++
+		switch token.kind {
+		case formulaCloseParen:
+			return call, nil
+		case formulaSeparator:
+		default:
+			return nil, errors.Errorf("unexpected %s in arguments of %s", token.text, name)
+		}
+	}
+}
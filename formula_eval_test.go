@@ -0,0 +1,167 @@
+package herschel
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newFormulaTestTable() *Table {
+	table := NewTable(6, 4)
+	table.PutValuesAtRow(0, "name", "price", "qty", "code")
+	table.PutValuesAtRow(1, "apple", 1.5, 4, "A")
+	table.PutValuesAtRow(2, "banana", 0.25, 12, "B")
+	table.PutValuesAtRow(3, "cherry", 4, "n/a", "C")
+	table.PutValuesAtRow(4, "date", 3, nil, "D")
+	table.PutValuesAtRow(5, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), true, "", nil)
+	return table
+}
+
+func TestEvaluateFormula(t *testing.T) {
+	table := newFormulaTestTable()
+	tests := []struct {
+		formula Formula
+		want    interface{}
+	}{
+		{"=1+2*3", 7.0},
+		{"=(1+2)*3", 9.0},
+		{"=2^3^2", 64.0},
+		{"=-2^2", 4.0},
+		{"=50%", 0.5},
+		{"=10/4", 2.5},
+		{"=1/0", FormulaErrorDivByZero},
+		{"=B2*C2", 6.0},
+		{"=B2+\"2\"", 3.5},
+		{"=A2+1", FormulaErrorValue},
+		{"=A2&\" \"&B2&\" \"&B6", "apple 1.5 TRUE"},
+		{"=B2>=1.5", true},
+		{"=A2=\"APPLE\"", true},
+		{"=A2<>\"apple\"", false},
+		{"=1<\"a\"", true},
+		{"=D5=\"\"", false},
+		{"=C5=0", true},
+		{"=A6", excelSerial(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))},
+		{"=SUM(B2:C5)", 24.75},
+		{"=SUM(B:B)", 8.75},
+		{"=SUM(B3:B)", 7.25},
+		{"=SUM(B3:3)", 12.25},
+		{"=SUM(B10:B)", 0.0},
+		{"=SUM(A1:XFD1048576)", 45317.75},
+		{"=SUM(XFD1048576:A1)", 45317.75},
+		{"=COUNTA(B5:C1000000)", 2.0},
+		{"=SUM(A100:B200)", 0.0},
+		{"=SUM(B2, 1, TRUE)", 3.5},
+		{"=SUM(A2:A3)", 0.0},
+		{"=SUM(\"x\")", FormulaErrorValue},
+		{"=AVERAGE(B2:B5)", 2.1875},
+		{"=AVERAGE(A2:A3)", FormulaErrorDivByZero},
+		{"=COUNT(B2:C6)", 6.0},
+		{"=COUNT(1, \"2\", \"x\")", 2.0},
+		{"=COUNTA(C2:D6)", 7.0},
+		{"=MIN(B2:B5)", 0.25},
+		{"=MAX(B2:B5, 10)", 10.0},
+		{"=MAX(A2:A3)", 0.0},
+		{"=IF(B2>1, \"high\", \"low\")", "high"},
+		{"=IF(B3>1, \"high\")", false},
+		{"=IF(B3>1, 1/0, 2)", 2.0},
+		{"=IFERROR(1/0, \"div\")", "div"},
+		{"=IFERROR(B2, \"div\")", 1.5},
+		{"=ROUND(2.5)", 3.0},
+		{"=ROUND(-2.345, 2)", -2.35},
+		{"=ROUND(1234, -2)", 1200.0},
+		{"=CONCATENATE(A2, \"-\", D2:D3, 1)", "apple-AB1"},
+		{"=VLOOKUP(\"banana\", A2:C5, 3, FALSE)", 12.0},
+		{"=VLOOKUP(\"BANANA\", A2:C5, 2, FALSE)", 0.25},
+		{"=VLOOKUP(\"fig\", A2:C5, 2, FALSE)", FormulaErrorNA},
+		{"=VLOOKUP(\"apple\", A2:C5, 4, FALSE)", FormulaErrorRef},
+		{"=VLOOKUP(\"bz\", A2:C5, 2)", 0.25},
+		{"=INDEX(A2:C5, 2, 3)", 12.0},
+		{"=INDEX(A2:A5, 3)", "cherry"},
+		{"=INDEX(A2:D2, 4)", "A"},
+		{"=INDEX(A2:C5, 5, 1)", FormulaErrorRef},
+		{"=SUM(INDEX(B2:C5, 0, 1))", 8.75},
+		{"=INDEX(A2:C5, 2.9, 3.1)", 12.0},
+		{"=INDEX(A2:C5, 0.5, 1)", FormulaErrorValue},
+		{"=SUM(INDEX(B2:C5, 0.5, 1.5))", 8.75},
+		{"=INDEX(A2:A5, 0, 0.5)", FormulaErrorValue},
+		{"=INDEX(A2:D2, 4.5)", "A"},
+		{"=INDEX(A2:C5, 1, 4)", FormulaErrorRef},
+		{"=INDEX(A2:C5, -1, 1)", FormulaErrorRef},
+		{"=INDEX(A2:C5, 5.5, 1)", FormulaErrorRef},
+		{"=INDEX(A1:B4, 1e300, 1)", FormulaErrorRef},
+		{"=INDEX(A1:B4, 1, 1e19)", FormulaErrorRef},
+		{"=INDEX(A1:B4, -1e19, 1)", FormulaErrorRef},
+		{"=INDEX(A2:A5, MATCH(\"C\", D2:D5, 0))", "cherry"},
+		{"=MATCH(1, B2:B3, -1)", 1.0},
+		{"=MATCH(5, {1}, 1)", FormulaErrorParse},
+		{"=MATCH(2, C2:C3, 1)", FormulaErrorNA},
+		{"=MATCH(13, C2:C3, 1)", 2.0},
+		{"=MATCH(\"z\", A2:B2, 0)", FormulaErrorNA},
+		{"=TEXT(B2*1000, \"#,##0.00\")", "1,500.00"},
+		{"=TEXT(A6, \"yyyy/mm/dd\")", "2024/01/02"},
+		{"=TEXT(\"0.5\", \"0%\")", "50%"},
+		{"=TEXT(1e308, \"0.00%\")", "#NUM!"},
+		{"=TEXT(7, \"@\")", "7"},
+		{"=A1:B2", FormulaErrorValue},
+		{"=Sheet2!A1", FormulaErrorRef},
+		{"=UNKNOWN(1)", FormulaErrorName},
+		{"=undefined_name", FormulaErrorName},
+		{"=#N/A", FormulaErrorNA},
+		{"=SUM(1,", FormulaErrorParse},
+		{"=(1+2", FormulaErrorParse},
+		{"=", FormulaErrorParse},
+	}
+	for _, tt := range tests {
+		if got := table.EvaluateFormula(tt.formula); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Unexpected result of %s: %#v, want %#v", tt.formula, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateCells(t *testing.T) {
+	table := NewTable(4, 3)
+	table.PutValuesAtRow(0, 1, Formula("=A1*2"), Formula("=B1+A1"))
+	table.PutValuesAtRow(1, Formula("=C1+B2"), Formula("=A2"), Formula("=IFERROR(A2, -1)"))
+	table.PutValuesAtRow(2, Formula("=A3"), Formula("=B1/A4"), Formula("=SUM(C1:C2)"))
+	table.PutValuesAtRow(3, 0, nil, "text")
+
+	tests := []struct {
+		row  int
+		col  int
+		want interface{}
+	}{
+		{0, 0, 1},
+		{0, 1, 2.0},
+		{0, 2, 3.0},
+		{1, 0, FormulaErrorRef},
+		{1, 1, FormulaErrorRef},
+		{1, 2, -1.0},
+		{2, 0, FormulaErrorRef},
+		{2, 1, FormulaErrorDivByZero},
+		{2, 2, 2.0},
+		{3, 2, "text"},
+	}
+	for _, tt := range tests {
+		if got := table.Evaluate(tt.row, tt.col); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Unexpected result at (%d, %d): %#v, want %#v", tt.row, tt.col, got, tt.want)
+		}
+	}
+
+	evaluated := table.Evaluated()
+	for _, tt := range tests {
+		if got := evaluated.GetValue(tt.row, tt.col); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Unexpected evaluated value at (%d, %d): %#v, want %#v", tt.row, tt.col, got, tt.want)
+		}
+	}
+	if _, ok := table.GetValue(0, 1).(Formula); !ok {
+		t.Errorf("Original table is modified: %#v", table.GetValue(0, 1))
+	}
+
+	table.SetNumberFormatPattern(0, 2, "0.00")
+	if got := table.GetDisplayString(0, 2); got != "3.00" {
+		t.Errorf("Unexpected display string of formula: %s", got)
+	}
+	if got := table.GetDisplayString(2, 1); got != "#DIV/0!" {
+		t.Errorf("Unexpected display string of error: %s", got)
+	}
+}
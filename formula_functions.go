@@ -0,0 +1,413 @@
+package herschel
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+type formulaFunc func(e *formulaEvaluator, args []formulaNode) interface{}
+
+// formulaFunctions are functions supported by the local evaluator, keyed by upper case names.
+var formulaFunctions map[string]formulaFunc
+
+func init() {
+	formulaFunctions = map[string]formulaFunc{
+		"SUM":         formulaSum,
+		"AVERAGE":     formulaAverage,
+		"COUNT":       formulaCount,
+		"COUNTA":      formulaCountA,
+		"MIN":         formulaMin,
+		"MAX":         formulaMax,
+		"IF":          formulaIf,
+		"IFERROR":     formulaIfError,
+		"ROUND":       formulaRound,
+		"CONCATENATE": formulaConcatenate,
+		"VLOOKUP":     formulaVLookup,
+		"INDEX":       formulaIndex,
+		"MATCH":       formulaMatch,
+		"TEXT":        formulaText,
+	}
+}
+
+// numericArguments returns numbers of arguments. Numbers in ranges are used and the other values in ranges are ignored,
+// while values given directly are converted to numbers.
+func numericArguments(e *formulaEvaluator, args []formulaNode) ([]float64, FormulaError) {
+	numbers := []float64{}
+	for _, arg := range args {
+		v := arg.eval(e)
+		if r, ok := v.(formulaRange); ok {
+			for _, value := range r.values() {
+				switch n := value.(type) {
+				case float64:
+					numbers = append(numbers, n)
+				case FormulaError:
+					return nil, n
+				}
+			}
+			continue
+		}
+		if _, empty := arg.(emptyNode); empty {
+			continue
+		}
+		n, err := toFormulaNumber(v)
+		if err != "" {
+			return nil, err
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, ""
+}
+
+func formulaSum(e *formulaEvaluator, args []formulaNode) interface{} {
+	numbers, err := numericArguments(e, args)
+	if err != "" {
+		return err
+	}
+	sum := 0.0
+	for _, n := range numbers {
+		sum += n
+	}
+	return sum
+}
+
+func formulaAverage(e *formulaEvaluator, args []formulaNode) interface{} {
+	numbers, err := numericArguments(e, args)
+	if err != "" {
+		return err
+	}
+	if len(numbers) == 0 {
+		return FormulaErrorDivByZero
+	}
+	sum := 0.0
+	for _, n := range numbers {
+		sum += n
+	}
+	return sum / float64(len(numbers))
+}
+
+func formulaMin(e *formulaEvaluator, args []formulaNode) interface{} {
+	numbers, err := numericArguments(e, args)
+	if err != "" {
+		return err
+	}
+	if len(numbers) == 0 {
+		return 0.0
+	}
+	min := numbers[0]
+	for _, n := range numbers[1:] {
+		min = math.Min(min, n)
+	}
+	return min
+}
+
+func formulaMax(e *formulaEvaluator, args []formulaNode) interface{} {
+	numbers, err := numericArguments(e, args)
+	if err != "" {
+		return err
+	}
+	if len(numbers) == 0 {
+		return 0.0
+	}
+	max := numbers[0]
+	for _, n := range numbers[1:] {
+		max = math.Max(max, n)
+	}
+	return max
+}
+
+// formulaCount counts numbers in ranges, and values given directly which can be converted to numbers.
+func formulaCount(e *formulaEvaluator, args []formulaNode) interface{} {
+	count := 0
+	for _, arg := range args {
+		v := arg.eval(e)
+		if r, ok := v.(formulaRange); ok {
+			for _, value := range r.values() {
+				if _, isNumber := value.(float64); isNumber {
+					count++
+				}
+			}
+			continue
+		}
+		if _, empty := arg.(emptyNode); empty {
+			continue
+		}
+		if _, err := toFormulaNumber(v); err == "" {
+			count++
+		}
+	}
+	return float64(count)
+}
+
+// formulaCountA counts non-empty values including errors.
+func formulaCountA(e *formulaEvaluator, args []formulaNode) interface{} {
+	count := 0
+	for _, arg := range args {
+		v := arg.eval(e)
+		if r, ok := v.(formulaRange); ok {
+			for _, value := range r.values() {
+				if value != nil {
+					count++
+				}
+			}
+			continue
+		}
+		if _, empty := arg.(emptyNode); !empty {
+			count++
+		}
+	}
+	return float64(count)
+}
+
+func formulaIf(e *formulaEvaluator, args []formulaNode) interface{} {
+	if len(args) < 2 || len(args) > 3 {
+		return FormulaErrorNA
+	}
+	condition, err := toFormulaBool(args[0].eval(e))
+	if err != "" {
+		return err
+	}
+	switch {
+	case condition:
+		return scalarValue(args[1].eval(e))
+	case len(args) == 3:
+		return scalarValue(args[2].eval(e))
+	}
+	return false
+}
+
+func formulaIfError(e *formulaEvaluator, args []formulaNode) interface{} {
+	if len(args) < 1 || len(args) > 2 {
+		return FormulaErrorNA
+	}
+	v := scalarValue(args[0].eval(e))
+	if _, isError := v.(FormulaError); !isError {
+		return v
+	}
+	if len(args) == 2 {
+		return scalarValue(args[1].eval(e))
+	}
+	return nil
+}
+
+func formulaRound(e *formulaEvaluator, args []formulaNode) interface{} {
+	if len(args) < 1 || len(args) > 2 {
+		return FormulaErrorNA
+	}
+	x, err := toFormulaNumber(args[0].eval(e))
+	if err != "" {
+		return err
+	}
+	digits := 0.0
+	if len(args) == 2 {
+		if digits, err = toFormulaNumber(args[1].eval(e)); err != "" {
+			return err
+		}
+	}
+	return roundHalfAwayFromZero(x, int(math.Trunc(digits)))
+}
+
+func formulaConcatenate(e *formulaEvaluator, args []formulaNode) interface{} {
+	var b strings.Builder
+	for _, arg := range args {
+		v := arg.eval(e)
+		values := []interface{}{v}
+		if r, ok := v.(formulaRange); ok {
+			values = r.values()
+		}
+		for _, value := range values {
+			s, err := toFormulaText(value)
+			if err != "" {
+				return err
+			}
+			b.WriteString(s)
+		}
+	}
+	return b.String()
+}
+
+// formulaVLookup looks up key in the first column of range. Range is assumed to be sorted unless the fourth argument is FALSE.
+func formulaVLookup(e *formulaEvaluator, args []formulaNode) interface{} {
+	if len(args) < 3 || len(args) > 4 {
+		return FormulaErrorNA
+	}
+	key := scalarValue(args[0].eval(e))
+	if err, ok := key.(FormulaError); ok {
+		return err
+	}
+	r, ok := args[1].eval(e).(formulaRange)
+	if !ok {
+		return FormulaErrorValue
+	}
+	index, err := toFormulaNumber(args[2].eval(e))
+	if err != "" {
+		return err
+	}
+	sorted := true
+	if len(args) == 4 {
+		if sorted, err = toFormulaBool(args[3].eval(e)); err != "" {
+			return err
+		}
+	}
+
+	col := int(index) - 1
+	switch {
+	case col < 0:
+		return FormulaErrorValue
+	case len(r) == 0 || col >= len(r[0]):
+		return FormulaErrorRef
+	}
+	keys := make([]interface{}, len(r))
+	for i, values := range r {
+		keys[i] = values[0]
+	}
+	matchType := 0
+	if sorted {
+		matchType = 1
+	}
+	i := lookupIndex(key, keys, matchType)
+	if i < 0 {
+		return FormulaErrorNA
+	}
+	return r[i][col]
+}
+
+// lookupIndex returns index of key in values, or -1 when not found.
+// matchType 0 finds equal value, 1 finds the largest value less than or equal to key in ascending values,
+// and -1 finds the smallest value greater than or equal to key in descending values.
+func lookupIndex(key interface{}, values []interface{}, matchType int) int {
+	found := -1
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		_, keyIsText := key.(string)
+		_, valueIsText := v.(string)
+		if keyIsText != valueIsText && matchType != 0 {
+			continue
+		}
+		c := compareFormulaValues(v, key)
+		switch {
+		case matchType == 0 && c == 0:
+			return i
+		case matchType > 0 && c <= 0:
+			found = i
+		case matchType > 0 && c > 0:
+			return found
+		case matchType < 0 && c >= 0:
+			found = i
+		case matchType < 0 && c < 0:
+			return found
+		}
+	}
+	return found
+}
+
+// formulaIndex returns value in range at row and column counted from 1.
+// A single index of a range of one row is treated as the column.
+func formulaIndex(e *formulaEvaluator, args []formulaNode) interface{} {
+	if len(args) < 2 || len(args) > 3 {
+		return FormulaErrorNA
+	}
+	r, ok := args[0].eval(e).(formulaRange)
+	if !ok {
+		return FormulaErrorValue
+	}
+	row, err := toFormulaNumber(args[1].eval(e))
+	if err != "" {
+		return err
+	}
+	col := 0.0
+	if len(args) == 3 {
+		if col, err = toFormulaNumber(args[2].eval(e)); err != "" {
+			return err
+		}
+	} else if len(r) == 1 {
+		row, col = 1, row
+	}
+	// Indices are truncated like other spreadsheet functions taking integers.
+	row, col = math.Trunc(row), math.Trunc(col)
+
+	// Indices are compared as float64 since huge ones overflow int.
+	if math.IsNaN(row) || math.IsNaN(col) || row < 0 || col < 0 || len(r) == 0 || row > float64(len(r)) || col > float64(len(r[0])) {
+		return FormulaErrorRef
+	}
+	switch {
+	case row == 0 && col == 0:
+		return r
+	case row == 0:
+		column := make(formulaRange, len(r))
+		for i, values := range r {
+			column[i] = []interface{}{values[int(col)-1]}
+		}
+		return column
+	case col == 0:
+		if len(r[0]) == 1 {
+			return r[int(row)-1][0]
+		}
+		return formulaRange{r[int(row)-1]}
+	}
+	return r[int(row)-1][int(col)-1]
+}
+
+// formulaMatch returns position of key in a range of one row or column counted from 1.
+func formulaMatch(e *formulaEvaluator, args []formulaNode) interface{} {
+	if len(args) < 2 || len(args) > 3 {
+		return FormulaErrorNA
+	}
+	key := scalarValue(args[0].eval(e))
+	if err, ok := key.(FormulaError); ok {
+		return err
+	}
+	r, ok := args[1].eval(e).(formulaRange)
+	if !ok {
+		return FormulaErrorNA
+	}
+	if len(r) > 1 && len(r[0]) > 1 {
+		return FormulaErrorNA
+	}
+	matchType := 1.0
+	if len(args) == 3 {
+		var err FormulaError
+		if matchType, err = toFormulaNumber(args[2].eval(e)); err != "" {
+			return err
+		}
+	}
+
+	mt := 0
+	switch {
+	case matchType > 0:
+		mt = 1
+	case matchType < 0:
+		mt = -1
+	}
+	i := lookupIndex(key, r.values(), mt)
+	if i < 0 {
+		return FormulaErrorNA
+	}
+	return float64(i + 1)
+}
+
+// formulaText formats value with number format pattern.
+func formulaText(e *formulaEvaluator, args []formulaNode) interface{} {
+	if len(args) != 2 {
+		return FormulaErrorNA
+	}
+	v := scalarValue(args[0].eval(e))
+	if err, ok := v.(FormulaError); ok {
+		return err
+	}
+	pattern, err := toFormulaText(args[1].eval(e))
+	if err != "" {
+		return err
+	}
+	if s, ok := v.(string); ok {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			v = f
+		}
+	}
+	if v == nil {
+		v = 0.0
+	}
+	return formatValue(v, pattern)
+}
@@ -0,0 +1,227 @@
+package herschel
+
+import (
+	"testing"
+)
+
+func TestTokenizeFormula(t *testing.T) {
+	tokens, err := tokenizeFormula(`=IF(Sheet2!$A$1>=10, SUM(B2:C3) & "a""b", 'My Sheet'!A:A) + 1.5E+3 - #N/A`)
+	if err != nil {
+		t.Fatalf("Failed to tokenize: %v", err)
+	}
+
+	expected := []struct {
+		kind formulaTokenKind
+		text string
+	}{
+		{formulaFunction, "IF"},
+		{formulaOpenParen, "("},
+		{formulaRef, "Sheet2!$A$1"},
+		{formulaOperator, ">="},
+		{formulaNumber, "10"},
+		{formulaSeparator, ","},
+		{formulaSpace, " "},
+		{formulaFunction, "SUM"},
+		{formulaOpenParen, "("},
+		{formulaRef, "B2:C3"},
+		{formulaCloseParen, ")"},
+		{formulaSpace, " "},
+		{formulaOperator, "&"},
+		{formulaSpace, " "},
+		{formulaString, `"a""b"`},
+		{formulaSeparator, ","},
+		{formulaSpace, " "},
+		{formulaRef, "'My Sheet'!A:A"},
+		{formulaCloseParen, ")"},
+		{formulaSpace, " "},
+		{formulaOperator, "+"},
+		{formulaSpace, " "},
+		{formulaNumber, "1.5E+3"},
+		{formulaSpace, " "},
+		{formulaOperator, "-"},
+		{formulaSpace, " "},
+		{formulaErrorValue, "#N/A"},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Unexpected number of tokens: %d, %+v", len(tokens), tokens)
+	}
+	for i, e := range expected {
+		if tokens[i].kind != e.kind || tokens[i].text != e.text {
+			t.Errorf("Unexpected token at %d: %+v, want %+v", i, tokens[i], e)
+		}
+	}
+
+	for _, invalid := range []string{`="abc`, `=A1 @ B1`, `=#WHAT`} {
+		if _, err := tokenizeFormula(invalid); err == nil {
+			t.Errorf("Error expected for %s", invalid)
+		}
+	}
+}
+
+func TestFormulaReferences(t *testing.T) {
+	tests := []struct {
+		text    string
+		isRef   bool
+		start   refPoint
+		isRange bool
+	}{
+		{"A1", true, refPoint{row: 0, col: 0}, false},
+		{"$B$3", true, refPoint{row: 2, col: 1, rowAbs: true, colAbs: true}, false},
+		{"c$10", true, refPoint{row: 9, col: 2, rowAbs: true}, false},
+		{"A:C", true, refPoint{row: -1, col: 0}, true},
+		{"2:$5", true, refPoint{row: 1, col: -1}, true},
+		{"B2:B", true, refPoint{row: 1, col: 1}, true},
+		{"$A2:2", true, refPoint{row: 1, col: 0, colAbs: true}, true},
+		{"LOG10(", false, refPoint{}, false},
+		{"A", false, refPoint{}, false},
+		{"ABCD1", false, refPoint{}, false},
+		{"A0", false, refPoint{}, false},
+	}
+	for _, tt := range tests {
+		ref, n, ok := readFormulaReference(tt.text)
+		if ok != tt.isRef {
+			t.Errorf("Unexpected result for %s: %v", tt.text, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if n != len(tt.text) || ref.start != tt.start || ref.isRange != tt.isRange {
+			t.Errorf("Unexpected reference for %s: %+v (%d)", tt.text, ref, n)
+		}
+	}
+}
+
+func TestFormulaShifted(t *testing.T) {
+	tests := []struct {
+		name    string
+		formula Formula
+		rowMap  indexMap
+		colMap  indexMap
+		want    Formula
+	}{
+		{"Offset", "=SUM(A1:B2)*$C$3", offsetMap(2), offsetMap(1), "=SUM(B3:C4)*$D$5"},
+		{"InsertColumn", "=A1+B1+C1+SUM(A1:C1)", nil, insertMap(1, 1), "=A1+C1+D1+SUM(A1:D1)"},
+		{"RemoveColumn", "=A1+B1+C1+SUM(A1:C1)", nil, removeMap(1), "=A1+#REF!+B1+SUM(A1:B1)"},
+		{"RemoveFirstColumnOfRange", "=SUM(B1:C1)+SUM(B1)", nil, removeMap(1), "=SUM(B1:B1)+SUM(#REF!)"},
+		{"WholeColumnsAndRows", "=SUM(B:B)+SUM(2:3)", offsetMap(1), offsetMap(1), "=SUM(C:C)+SUM(3:4)"},
+		{"OutOfTable", "=A1+B2", offsetMap(-1), nil, "=#REF!+B1"},
+		{"OtherSheet", "=Sheet2!A1+'My Sheet'!B2+A1", offsetMap(1), offsetMap(1), "=Sheet2!A1+'My Sheet'!B2+B2"},
+		{"StringsAndFunctions", `=CONCATENATE("A1", LOG10(A1))`, offsetMap(1), nil, `=CONCATENATE("A1", LOG10(A2))`},
+		{"OpenEndedRanges", "=SUM(B2:B)+SUM(B2:2)", offsetMap(1), offsetMap(1), "=SUM(C3:C)+SUM(C3:3)"},
+		{"InsertColumnBeforeOpenEndedRange", "=B1:B+C1", nil, insertMap(1, 1), "=C1:C+D1"},
+		{"RemoveStartOfOpenEndedRange", "=SUM(B2:B)+SUM(A3:3)", removeMap(1), removeMap(0), "=SUM(A2:A)+SUM(A2:2)"},
+		{"RemoveOpenEndedColumn", "=SUM(A1:A)", nil, offsetMap(-1), "=SUM(#REF!)"},
+		{"Unparsable", `="A1`, offsetMap(1), nil, `="A1`},
+		{"UnparsableWithReferences", `=B1+{1,2}`, nil, insertMap(1, 1), `=B1+{1,2}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formula.shifted(tt.rowMap, tt.colMap); got != tt.want {
+				t.Errorf("Unexpected formula: %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormulasInTableManipulation(t *testing.T) {
+	newTable := func() *Table {
+		table := NewTable(3, 3)
+		table.PutValuesAtRow(0, 1, 2, Formula("=A1+B1"))
+		table.PutValuesAtRow(1, 3, 4, Formula("=SUM(A1:B2)"))
+		table.PutValuesAtRow(2, Formula("=A2*2"), Formula("=B1:B+C1"), nil)
+		return table
+	}
+
+	t.Run("InsertColAtIndex", func(t *testing.T) {
+		table := newTable()
+		if err := table.InsertColAtIndex(1); err != nil {
+			t.Fatal(err)
+		}
+		if got := table.GetValue(0, 3); got != Formula("=A1+C1") {
+			t.Errorf("Unexpected formula: %v", got)
+		}
+		if got := table.GetValue(1, 3); got != Formula("=SUM(A1:C2)") {
+			t.Errorf("Unexpected formula: %v", got)
+		}
+		if got := table.GetValue(2, 2); got != Formula("=C1:C+D1") {
+			t.Errorf("Unexpected formula: %v", got)
+		}
+	})
+
+	t.Run("RemoveColAtIndex", func(t *testing.T) {
+		table := newTable()
+		if err := table.RemoveColAtIndex(0); err != nil {
+			t.Fatal(err)
+		}
+		if got := table.GetValue(0, 1); got != Formula("=#REF!+A1") {
+			t.Errorf("Unexpected formula: %v", got)
+		}
+		if got := table.GetValue(1, 1); got != Formula("=SUM(A1:A2)") {
+			t.Errorf("Unexpected formula: %v", got)
+		}
+	})
+
+	t.Run("AppendTableAtBottom", func(t *testing.T) {
+		table := newTable().AppendTableAtBottom(newTable())
+		if got := table.GetValue(2, 0); got != Formula("=A2*2") {
+			t.Errorf("Unexpected formula of original table: %v", got)
+		}
+		if got := table.GetValue(4, 2); got != Formula("=SUM(A4:B5)") {
+			t.Errorf("Unexpected formula of appended table: %v", got)
+		}
+	})
+
+	t.Run("AppendTableAtRight", func(t *testing.T) {
+		table := newTable().AppendTableAtRight(newTable())
+		if got := table.GetValue(0, 5); got != Formula("=D1+E1") {
+			t.Errorf("Unexpected formula of appended table: %v", got)
+		}
+	})
+
+	t.Run("SubTable", func(t *testing.T) {
+		table, err := newTable().SubTable(1, 0, 2, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := table.GetValue(0, 2); got != Formula("=SUM(A1:B1)") {
+			t.Errorf("Unexpected formula: %v", got)
+		}
+		if got := table.GetValue(1, 0); got != Formula("=A1*2") {
+			t.Errorf("Unexpected formula: %v", got)
+		}
+	})
+
+	t.Run("SubTableByFilteringRows", func(t *testing.T) {
+		table := newTable().SubTableByFilteringRows(func(values []interface{}) bool {
+			return values[0] != 1
+		})
+		if got := table.GetValue(0, 2); got != Formula("=SUM(A1:B1)") {
+			t.Errorf("Unexpected formula: %v", got)
+		}
+		if got := table.GetValue(1, 0); got != Formula("=A1*2") {
+			t.Errorf("Unexpected formula: %v", got)
+		}
+	})
+
+	t.Run("withOffset", func(t *testing.T) {
+		table := newTable()
+		table.SetColumnWidth(2, 120)
+		moved := table.withOffset(1, 2)
+		if moved.GetRows() != 4 || moved.GetCols() != 5 {
+			t.Fatalf("Unexpected size: %d x %d", moved.GetRows(), moved.GetCols())
+		}
+		if got := moved.GetValue(2, 4); got != Formula("=SUM(C2:D3)") {
+			t.Errorf("Unexpected formula: %v", got)
+		}
+		if moved.GetColumnWidth(4) != 120 {
+			t.Errorf("Unexpected column width: %d", moved.GetColumnWidth(4))
+		}
+	})
+
+	t.Run("userEnteredString", func(t *testing.T) {
+		if got := userEnteredString(Formula("=A1")); got != "=A1" {
+			t.Errorf("Unexpected user entered string: %s", got)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package herschel
+
+import (
+	"sync"
+	"time"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+// metadataCache caches sheet title to ID lookups per spreadsheet.
+type metadataCache struct {
+	mu       sync.Mutex
+	disabled bool
+	ttl      time.Duration
+	now      func() time.Time
+	entries  map[string]metadataCacheEntry
+}
+
+type metadataCacheEntry struct {
+	sheetIDs  map[string]int64
+	fetchedAt time.Time
+}
+
+func newMetadataCache() *metadataCache {
+	return &metadataCache{now: time.Now, entries: map[string]metadataCacheEntry{}}
+}
+
+// sheetID returns cached ID of sheet with title. Unknown titles are not cached,
+// since the sheet may have been added or renamed outside of the client.
+func (c *metadataCache) sheetID(spreadsheetID string, title string) (id int64, cached bool) {
+	if c == nil {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.disabled {
+		return 0, false
+	}
+	entry, ok := c.entries[spreadsheetID]
+	if !ok {
+		return 0, false
+	}
+	if c.ttl > 0 && c.now().Sub(entry.fetchedAt) > c.ttl {
+		delete(c.entries, spreadsheetID)
+		return 0, false
+	}
+	id, cached = entry.sheetIDs[title]
+	return id, cached
+}
+
+func (c *metadataCache) store(spreadsheetID string, properties []*sheets.SheetProperties) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.disabled {
+		return
+	}
+	ids := map[string]int64{}
+	for _, p := range properties {
+		ids[p.Title] = p.SheetId
+	}
+	c.entries[spreadsheetID] = metadataCacheEntry{sheetIDs: ids, fetchedAt: c.now()}
+}
+
+func (c *metadataCache) invalidate(spreadsheetID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, spreadsheetID)
+}
+
+func (c *metadataCache) setTTL(ttl time.Duration) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ttl = ttl
+}
+
+func (c *metadataCache) disable() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.disabled = true
+	c.entries = map[string]metadataCacheEntry{}
+}
+
+func (c *metadataCache) enable() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.disabled = false
+}
+
+// SetMetadataCacheTTL sets how long sheet title to ID lookups are cached. Zero ttl keeps them until the sheets are changed through this client.
+func (client Client) SetMetadataCacheTTL(ttl time.Duration) {
+	client.cache.setTTL(ttl)
+}
+
+// DisableMetadataCache disables caching of sheet title to ID lookups. The TTL is kept.
+func (client Client) DisableMetadataCache() {
+	client.cache.disable()
+}
+
+// EnableMetadataCache enables caching of sheet title to ID lookups disabled by DisableMetadataCache.
+func (client Client) EnableMetadataCache() {
+	client.cache.enable()
+}
+
+// InvalidateMetadataCache drops cached sheet metadata of spreadsheet. Call this after sheets are deleted or recreated outside of this client.
+// Titles not in the cache are always fetched again, and the cache is also dropped when a batch update of the spreadsheet fails.
+func (client Client) InvalidateMetadataCache(spreadsheetID string) {
+	client.cache.invalidate(spreadsheetID)
+}
@@ -0,0 +1,80 @@
+package herschel
+
+import (
+	"testing"
+	"time"
+
+	sheets "google.golang.org/api/sheets/v4"
+)
+
+func TestMetadataCache(t *testing.T) {
+	now := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	c := newMetadataCache()
+	c.now = func() time.Time { return now }
+
+	if _, cached := c.sheetID("ss", "Sheet1"); cached {
+		t.Fatal("Empty cache should not have entries.")
+	}
+
+	c.store("ss", []*sheets.SheetProperties{{SheetId: 0, Title: "Sheet1"}, {SheetId: 123, Title: "Sheet2"}})
+
+	t.Run("Hit", func(t *testing.T) {
+		id, cached := c.sheetID("ss", "Sheet2")
+		if !cached || id != 123 {
+			t.Errorf("Sheet2 should be cached with ID 123, got: %d, %v", id, cached)
+		}
+	})
+
+	t.Run("MissingTitle", func(t *testing.T) {
+		// Sheet3 may have been added outside of the client, so it should be fetched again.
+		if _, cached := c.sheetID("ss", "Sheet3"); cached {
+			t.Error("Missing title should not be cached.")
+		}
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		c.setTTL(time.Minute)
+		now = now.Add(30 * time.Second)
+		if _, cached := c.sheetID("ss", "Sheet1"); !cached {
+			t.Error("Entry should be cached before ttl.")
+		}
+		now = now.Add(time.Minute)
+		if _, cached := c.sheetID("ss", "Sheet1"); cached {
+			t.Error("Entry should be expired after ttl.")
+		}
+	})
+
+	t.Run("Invalidate", func(t *testing.T) {
+		c.store("ss", []*sheets.SheetProperties{{SheetId: 0, Title: "Sheet1"}})
+		c.invalidate("ss")
+		if _, cached := c.sheetID("ss", "Sheet1"); cached {
+			t.Error("Entry should be invalidated.")
+		}
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		c.disable()
+		c.store("ss", []*sheets.SheetProperties{{SheetId: 0, Title: "Sheet1"}})
+		if _, cached := c.sheetID("ss", "Sheet1"); cached {
+			t.Error("Disabled cache should not have entries.")
+		}
+		c.setTTL(time.Hour)
+		c.store("ss", []*sheets.SheetProperties{{SheetId: 0, Title: "Sheet1"}})
+		if _, cached := c.sheetID("ss", "Sheet1"); cached {
+			t.Error("Setting ttl should not enable the cache.")
+		}
+		c.enable()
+		c.store("ss", []*sheets.SheetProperties{{SheetId: 0, Title: "Sheet1"}})
+		if _, cached := c.sheetID("ss", "Sheet1"); !cached {
+			t.Error("Enabled cache should have entries.")
+		}
+	})
+
+	t.Run("NilCache", func(t *testing.T) {
+		var nilCache *metadataCache
+		nilCache.store("ss", nil)
+		if _, cached := nilCache.sheetID("ss", "Sheet1"); cached {
+			t.Error("Nil cache should not have entries.")
+		}
+	})
+}
@@ -0,0 +1,915 @@
+package herschel
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// defaultNumberFormatPatterns are patterns applied to number format types without patterns, as spreadsheet does in en_US locale.
+var defaultNumberFormatPatterns = map[string]string{
+	"NUMBER":     "#,##0.00",
+	"PERCENT":    "0.00%",
+	"CURRENCY":   "$#,##0.00",
+	"DATE":       "m/d/yyyy",
+	"TIME":       "h:mm:ss AM/PM",
+	"DATE_TIME":  "m/d/yyyy h:mm:ss",
+	"SCIENTIFIC": "0.00E+00",
+}
+
+type formatTokenKind int
+
+const (
+	formatLiteral formatTokenKind = iota
+	// formatDigit is a digit placeholder 0, # or ?.
+	formatDigit
+	formatDecimalPoint
+	// formatComma is a thousands separator, a scaling by 1000 or a literal depending on its position.
+	formatComma
+	// formatExponent is E+ or E- of scientific notation.
+	formatExponent
+	// formatSlash separates numerator and denominator of fraction.
+	formatSlash
+	// formatDenominator is a fixed denominator of fraction such as 100 in # ??/100.
+	formatDenominator
+	// formatText is @, replaced by text.
+	formatText
+	// formatDate is a date or time token such as yyyy, mm (month), nn (minute), [h] or .000 (fraction of second).
+	formatDate
+	// formatAMPM is AM/PM or A/P.
+	formatAMPM
+)
+
+type formatToken struct {
+	kind formatTokenKind
+	text string
+}
+
+// formatSection is a section of number format pattern separated by ';'.
+type formatSection struct {
+	tokens    []formatToken
+	condition func(float64) bool
+	percent   int
+	date      bool
+	text      bool
+	numeric   bool
+}
+
+// formatValue returns v formatted with number format pattern as spreadsheet displays it.
+func formatValue(v interface{}, pattern string) string {
+	isGeneral := len(pattern) == 0 || strings.EqualFold(pattern, "General")
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if isGeneral {
+			return value
+		}
+		return formatTextValue(value, parseFormatSections(pattern))
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(value))
+	case time.Time:
+		if isGeneral {
+			return userEnteredTime(value)
+		}
+		sections := parseFormatSections(pattern)
+		if s := sections[0]; s.date && s.condition == nil {
+			return s.formatTime(value, excelSerial(value))
+		}
+		return formatNumber(excelSerial(value), pattern)
+	}
+
+	f, ok := toFloat64(v)
+	if !ok {
+		return userEnteredString(v)
+	}
+	if isGeneral {
+		if _, isFloat := v.(float64); isFloat {
+			return formatGeneral(f)
+		}
+		return userEnteredString(v)
+	}
+	return formatNumber(f, pattern)
+}
+
+// formatGeneral formats number with up to 15 significant digits, hiding errors of floating point arithmetic.
+func formatGeneral(f float64) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "#NUM!"
+	}
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(f, 'g', 15, 64), 64)
+	if err != nil {
+		rounded = f
+	}
+	if a := math.Abs(rounded); a != 0 && (a >= 1e15 || a < 1e-9) {
+		return strings.ToUpper(strconv.FormatFloat(rounded, 'e', -1, 64))
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// formatNumber formats value with number format pattern.
+// Sections for positive numbers, negative numbers, zero and text, and conditions such as [>100] are supported.
+func formatNumber(value float64, pattern string) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "#NUM!"
+	}
+	sections := parseFormatSections(pattern)
+
+	conditional := false
+	for _, s := range sections {
+		if s.condition != nil {
+			conditional = true
+			if s.condition(value) {
+				return s.format(value, true)
+			}
+		}
+	}
+	if conditional {
+		for _, s := range sections {
+			if s.condition == nil && !s.isTextOnly() {
+				return s.format(value, true)
+			}
+		}
+		return formatGeneral(value)
+	}
+
+	numeric := sections
+	if len(numeric) > 3 {
+		numeric = numeric[:3]
+	}
+	switch {
+	case value < 0 && len(numeric) >= 2 && !numeric[1].isTextOnly():
+		// The negative section has its own sign.
+		return numeric[1].format(-value, false)
+	case value == 0 && len(numeric) >= 3:
+		return numeric[2].format(value, false)
+	}
+	return numeric[0].format(value, true)
+}
+
+// formatTextValue formats text with the text section of pattern, or returns text as is without it.
+func formatTextValue(text string, sections []formatSection) string {
+	var s formatSection
+	switch {
+	case len(sections) >= 4:
+		s = sections[3]
+	case sections[len(sections)-1].isTextOnly():
+		s = sections[len(sections)-1]
+	default:
+		return text
+	}
+
+	var b strings.Builder
+	for _, token := range s.tokens {
+		switch token.kind {
+		case formatText:
+			b.WriteString(text)
+		case formatLiteral:
+			b.WriteString(token.text)
+		}
+	}
+	return b.String()
+}
+
+func (s formatSection) isTextOnly() bool {
+	return s.text && !s.numeric && !s.date
+}
+
+// parseFormatSections splits pattern by ';' outside of quotes and parses sections.
+func parseFormatSections(pattern string) []formatSection {
+	sections := []formatSection{}
+	quoted, bracket := false, false
+	start := 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			if !quoted {
+				i++
+			}
+		case '[':
+			bracket = !quoted
+		case ']':
+			bracket = false
+		case ';':
+			if !quoted && !bracket {
+				sections = append(sections, parseFormatSection(pattern[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(sections, parseFormatSection(pattern[start:]))
+}
+
+func parseFormatSection(section string) formatSection {
+	s := formatSection{}
+	runes := []rune(section)
+	literal := func(text string) {
+		s.tokens = append(s.tokens, formatToken{kind: formatLiteral, text: text})
+	}
+	add := func(kind formatTokenKind, text string) {
+		s.tokens = append(s.tokens, formatToken{kind: kind, text: text})
+	}
+	// run returns length of run of rune at i, ignoring case.
+	run := func(i int) int {
+		n := 1
+		for i+n < len(runes) && unicode.ToLower(runes[i+n]) == unicode.ToLower(runes[i]) {
+			n++
+		}
+		return n
+	}
+	lastDate := func() string {
+		for i := len(s.tokens) - 1; i >= 0; i-- {
+			if s.tokens[i].kind == formatDate {
+				return s.tokens[i].text
+			}
+		}
+		return ""
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		lower := unicode.ToLower(r)
+		switch {
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end > len(runes) {
+				end = len(runes)
+			}
+			literal(string(runes[i+1 : end]))
+			i = end
+		case r == '\\' && i+1 < len(runes):
+			i++
+			literal(string(runes[i]))
+		case r == '_' && i+1 < len(runes):
+			// Space with width of the next character.
+			i++
+			literal(" ")
+		case r == '*' && i+1 < len(runes):
+			// Repeated fill character can not be rendered in text.
+			i++
+		case r == '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			inner := string(runes[i+1 : end])
+			i = end
+			switch {
+			case strings.HasPrefix(inner, "$"):
+				// Currency such as [$€-407]
+				literal(strings.SplitN(inner[1:], "-", 2)[0])
+			case strings.Trim(strings.ToLower(inner), "hms") == "" && len(inner) > 0:
+				add(formatDate, "["+strings.ToLower(inner)+"]")
+				s.date = true
+			default:
+				if c := parseFormatCondition(inner); c != nil {
+					s.condition = c
+				}
+				// Colors such as [Red] are ignored.
+			}
+		case r == '0' || r == '#' || r == '?':
+			add(formatDigit, string(r))
+			s.numeric = true
+		case r == '.':
+			if strings.HasPrefix(lastDate(), "s") && i+1 < len(runes) && runes[i+1] == '0' {
+				n := run(i + 1)
+				add(formatDate, "."+strings.Repeat("0", n))
+				i += n
+			} else {
+				add(formatDecimalPoint, ".")
+			}
+		case r == ',':
+			add(formatComma, ",")
+		case r == '%':
+			s.percent++
+			literal("%")
+		case (r == 'E' || r == 'e') && s.numeric && i+1 < len(runes) && (runes[i+1] == '+' || runes[i+1] == '-'):
+			add(formatExponent, string(runes[i:i+2]))
+			i++
+		case r == '/' && s.numeric && !s.date:
+			add(formatSlash, "/")
+			if i+1 < len(runes) && runes[i+1] >= '1' && runes[i+1] <= '9' {
+				end := i + 1
+				for end < len(runes) && runes[end] >= '0' && runes[end] <= '9' {
+					end++
+				}
+				add(formatDenominator, string(runes[i+1:end]))
+				i = end - 1
+			}
+		case r == '@':
+			add(formatText, "@")
+			s.text = true
+		case lower == 'a' && strings.EqualFold(string(runes[i:minInt(i+5, len(runes))]), "am/pm"):
+			add(formatAMPM, string(runes[i:i+5]))
+			i += 4
+		case lower == 'a' && strings.EqualFold(string(runes[i:minInt(i+3, len(runes))]), "a/p"):
+			add(formatAMPM, string(runes[i:i+3]))
+			i += 2
+		case lower == 'y' || lower == 'm' || lower == 'd' || lower == 'h' || lower == 's':
+			n := run(i)
+			add(formatDate, strings.Repeat(string(lower), n))
+			s.date = true
+			i += n - 1
+		default:
+			literal(string(r))
+		}
+	}
+
+	if s.date {
+		s.resolveMinutes()
+	}
+	return s
+}
+
+// resolveMinutes replaces m after hours or before seconds by n, which means minutes.
+func (s *formatSection) resolveMinutes() {
+	dates := []int{}
+	for i, token := range s.tokens {
+		if token.kind == formatDate {
+			dates = append(dates, i)
+		}
+	}
+	for j, i := range dates {
+		text := s.tokens[i].text
+		if text != "m" && text != "mm" {
+			continue
+		}
+		afterHours := j > 0 && strings.Contains(s.tokens[dates[j-1]].text, "h")
+		beforeSeconds := j+1 < len(dates) && strings.HasPrefix(strings.Trim(s.tokens[dates[j+1]].text, "["), "s")
+		if afterHours || beforeSeconds {
+			s.tokens[i].text = strings.Repeat("n", len(text))
+		}
+	}
+}
+
+func parseFormatCondition(s string) func(float64) bool {
+	for _, op := range []string{"<=", ">=", "<>", "<", ">", "="} {
+		if !strings.HasPrefix(s, op) {
+			continue
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(s[len(op):]), 64)
+		if err != nil {
+			return nil
+		}
+		switch op {
+		case "<=":
+			return func(v float64) bool { return v <= threshold }
+		case ">=":
+			return func(v float64) bool { return v >= threshold }
+		case "<>":
+			return func(v float64) bool { return v != threshold }
+		case "<":
+			return func(v float64) bool { return v < threshold }
+		case ">":
+			return func(v float64) bool { return v > threshold }
+		}
+		return func(v float64) bool { return v == threshold }
+	}
+	return nil
+}
+
+// format formats value with section. Negative value has a minus sign when signed.
+func (s formatSection) format(value float64, signed bool) string {
+	if s.date {
+		return s.formatTime(serialToTime(value), value)
+	}
+
+	var text string
+	if !s.numeric {
+		if s.text {
+			// Numbers are written as they are in the text placeholder.
+			return formatTextValue(formatGeneral(value), []formatSection{s})
+		}
+		text = s.formatLiterals()
+	} else {
+		text = s.formatDigits(math.Abs(value))
+	}
+	if signed && value < 0 && strings.ContainsAny(text, "123456789") {
+		return "-" + text
+	}
+	return text
+}
+
+func (s formatSection) formatLiterals() string {
+	var b strings.Builder
+	for _, token := range s.tokens {
+		if token.kind == formatLiteral || token.kind == formatComma {
+			b.WriteString(token.text)
+		}
+	}
+	return b.String()
+}
+
+// digitPart is a part of number where a digit placeholder belongs.
+type digitPart int
+
+const (
+	partInteger digitPart = iota
+	partDecimal
+	partExponent
+	partNumerator
+	partDenominator
+)
+
+// formatDigits formats non-negative value with digit placeholders of section.
+func (s formatSection) formatDigits(value float64) string {
+	for i := 0; i < s.percent; i++ {
+		value *= 100
+	}
+
+	parts := make([]digitPart, len(s.tokens))
+	placeholders := map[digitPart]string{}
+	grouping := false
+	fixedDenominator := 0
+	part := partInteger
+	for i, token := range s.tokens {
+		switch token.kind {
+		case formatDecimalPoint:
+			part = partDecimal
+		case formatExponent:
+			part = partExponent
+		case formatSlash:
+			part = partDenominator
+			// Contiguous placeholders before the slash are the numerator, taken from the part they were counted in.
+			for j := i - 1; j >= 0 && s.tokens[j].kind == formatDigit; j-- {
+				from := placeholders[parts[j]]
+				placeholders[parts[j]] = from[:len(from)-len(s.tokens[j].text)]
+				parts[j] = partNumerator
+				placeholders[partNumerator] = s.tokens[j].text + placeholders[partNumerator]
+			}
+		case formatDenominator:
+			fixedDenominator, _ = strconv.Atoi(token.text)
+		case formatDigit:
+			placeholders[part] += token.text
+		case formatComma:
+			if s.digitBefore(i) && s.digitAfter(i) && part == partInteger {
+				grouping = true
+			} else if s.digitBefore(i) && !s.digitAfter(i) {
+				value /= 1000
+			}
+		}
+		parts[i] = part
+	}
+	// Scaling by percents may overflow.
+	if math.IsInf(value, 0) {
+		return "#NUM!"
+	}
+
+	digits := map[digitPart][]string{}
+	exponentSign := ""
+	switch {
+	case len(placeholders[partNumerator]) > 0 || fixedDenominator > 0:
+		whole, numerator, denominator := fraction(value, len(placeholders[partInteger]) > 0, len(placeholders[partDenominator]), fixedDenominator)
+		// Decimals and exponents are meaningless in fractions, so their placeholders are filled as zero.
+		digits[partDecimal] = fillDecimal(placeholders[partDecimal], strings.Repeat("0", len(placeholders[partDecimal])))
+		digits[partExponent] = fillInteger(placeholders[partExponent], "", false)
+		digits[partInteger] = fillInteger(placeholders[partInteger], wholeDigits(whole), grouping)
+		if numerator == 0 && whole > 0 {
+			// Fraction is hidden for whole numbers.
+			digits[partNumerator] = blankDigits(placeholders[partNumerator])
+			digits[partDenominator] = blankDigits(placeholders[partDenominator])
+			return s.render(parts, digits, exponentSign, true)
+		}
+		digits[partNumerator] = fillInteger(placeholders[partNumerator], strconv.Itoa(numerator), false)
+		digits[partDenominator] = fillDenominator(placeholders[partDenominator], strconv.Itoa(denominator))
+	case s.hasExponent():
+		mantissa, exponent := scientific(value, placeholders[partInteger], len(placeholders[partDecimal]))
+		intDigits, decDigits := splitDigits(mantissa, len(placeholders[partDecimal]))
+		digits[partInteger] = fillInteger(placeholders[partInteger], intDigits, grouping)
+		digits[partDecimal] = fillDecimal(placeholders[partDecimal], decDigits)
+		if exponent < 0 {
+			exponentSign = "-"
+			exponent = -exponent
+		} else if strings.HasSuffix(s.exponentToken(), "+") {
+			exponentSign = "+"
+		}
+		digits[partExponent] = fillInteger(placeholders[partExponent], strconv.Itoa(exponent), false)
+	default:
+		intDigits, decDigits := splitDigits(value, len(placeholders[partDecimal]))
+		digits[partInteger] = fillInteger(placeholders[partInteger], intDigits, grouping)
+		digits[partDecimal] = fillDecimal(placeholders[partDecimal], decDigits)
+	}
+	return s.render(parts, digits, exponentSign, false)
+}
+
+// render writes tokens replacing placeholders with digits.
+func (s formatSection) render(parts []digitPart, digits map[digitPart][]string, exponentSign string, hideFraction bool) string {
+	var b strings.Builder
+	used := map[digitPart]int{}
+	for i, token := range s.tokens {
+		part := parts[i]
+		switch token.kind {
+		case formatDigit:
+			// Placeholders of a part unused by the number, such as a denominator without numerator, are left blank.
+			if used[part] < len(digits[part]) {
+				b.WriteString(digits[part][used[part]])
+			}
+			used[part]++
+		case formatDecimalPoint:
+			b.WriteString(".")
+		case formatExponent:
+			b.WriteString(token.text[:1] + exponentSign)
+		case formatSlash:
+			if hideFraction {
+				b.WriteString(" ")
+			} else {
+				b.WriteString("/")
+			}
+		case formatDenominator:
+			if hideFraction {
+				b.WriteString(strings.Repeat(" ", len(token.text)))
+			} else {
+				b.WriteString(token.text)
+			}
+		case formatComma:
+			if !s.digitBefore(i) && !s.digitAfter(i) || !s.digitBefore(i) {
+				b.WriteString(",")
+			}
+		case formatLiteral:
+			b.WriteString(token.text)
+		}
+	}
+	return b.String()
+}
+
+func (s formatSection) digitBefore(i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		if s.tokens[j].kind == formatDigit {
+			return true
+		}
+	}
+	return false
+}
+
+func (s formatSection) digitAfter(i int) bool {
+	for j := i + 1; j < len(s.tokens); j++ {
+		switch s.tokens[j].kind {
+		case formatDigit:
+			return true
+		case formatDecimalPoint, formatExponent:
+			return false
+		}
+	}
+	return false
+}
+
+func (s formatSection) hasExponent() bool {
+	return len(s.exponentToken()) > 0
+}
+
+func (s formatSection) exponentToken() string {
+	for _, token := range s.tokens {
+		if token.kind == formatExponent {
+			return token.text
+		}
+	}
+	return ""
+}
+
+// splitDigits rounds value to decimals and returns digits of integer part, without leading zero, and decimal part.
+func splitDigits(value float64, decimals int) (string, string) {
+	s := strconv.FormatFloat(roundHalfAwayFromZero(value, decimals), 'f', decimals, 64)
+	intDigits, decDigits := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intDigits, decDigits = s[:i], s[i+1:]
+	}
+	if intDigits == "0" {
+		intDigits = ""
+	}
+	return intDigits, decDigits
+}
+
+// roundHalfAwayFromZero rounds value to decimals, as spreadsheet does.
+func roundHalfAwayFromZero(value float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	if rounded := math.Round(value*scale) / scale; !math.IsInf(rounded, 0) && !math.IsNaN(rounded) {
+		return rounded
+	}
+	return value
+}
+
+// fillInteger returns text for each placeholder, filling digits from the right. Extra digits go to the first placeholder.
+func fillInteger(placeholders string, digits string, grouping bool) []string {
+	filled := make([]string, len(placeholders))
+	rest := digits
+	for i := len(placeholders) - 1; i >= 0; i-- {
+		if len(rest) > 0 {
+			filled[i] = rest[len(rest)-1:]
+			rest = rest[:len(rest)-1]
+			continue
+		}
+		switch placeholders[i] {
+		case '0':
+			filled[i] = "0"
+		case '?':
+			filled[i] = " "
+		}
+	}
+	if len(filled) > 0 {
+		filled[0] = rest + filled[0]
+	}
+	if grouping {
+		groupFilledDigits(filled)
+	}
+	return filled
+}
+
+// groupFilledDigits inserts thousands separators into digits spread over placeholders.
+func groupFilledDigits(filled []string) {
+	total := 0
+	for _, f := range filled {
+		total += strings.Count(f, "") - 1 - strings.Count(f, " ")
+	}
+	seen := 0
+	for i, f := range filled {
+		var b strings.Builder
+		for _, c := range f {
+			b.WriteRune(c)
+			if c == ' ' {
+				continue
+			}
+			seen++
+			if rest := total - seen; rest > 0 && rest%3 == 0 {
+				b.WriteByte(',')
+			}
+		}
+		filled[i] = b.String()
+	}
+}
+
+// fillDecimal returns text for each placeholder of decimals. Trailing zeros of # and ? are hidden.
+func fillDecimal(placeholders string, digits string) []string {
+	filled := make([]string, len(placeholders))
+	trailing := true
+	for i := len(placeholders) - 1; i >= 0; i-- {
+		d := digits[i : i+1]
+		if trailing && d == "0" && placeholders[i] != '0' {
+			if placeholders[i] == '?' {
+				filled[i] = " "
+			}
+			continue
+		}
+		trailing = false
+		filled[i] = d
+	}
+	return filled
+}
+
+func fillDenominator(placeholders string, digits string) []string {
+	filled := make([]string, len(placeholders))
+	for i := range placeholders {
+		switch {
+		case i < len(digits):
+			filled[i] = digits[i : i+1]
+		case placeholders[i] == '?':
+			filled[i] = " "
+		case placeholders[i] == '0':
+			filled[i] = "0"
+		}
+	}
+	if len(digits) > len(placeholders) && len(filled) > 0 {
+		filled[len(filled)-1] += digits[len(placeholders):]
+	}
+	return filled
+}
+
+func blankDigits(placeholders string) []string {
+	filled := make([]string, len(placeholders))
+	for i := range filled {
+		filled[i] = " "
+	}
+	return filled
+}
+
+func wholeDigits(whole int64) string {
+	if whole == 0 {
+		return ""
+	}
+	return strconv.FormatInt(whole, 10)
+}
+
+// fraction returns whole part, numerator and denominator approximating value.
+// The denominator has at most denominatorDigits digits, or is fixedDenominator if not zero.
+func fraction(value float64, hasWhole bool, denominatorDigits int, fixedDenominator int) (int64, int, int) {
+	whole := int64(0)
+	if hasWhole {
+		whole = int64(math.Floor(value))
+		value -= float64(whole)
+	}
+
+	if fixedDenominator > 0 {
+		numerator := int(math.Round(value * float64(fixedDenominator)))
+		if hasWhole && numerator == fixedDenominator {
+			return whole + 1, 0, fixedDenominator
+		}
+		return whole, numerator, fixedDenominator
+	}
+
+	maxDenominator := int(math.Pow10(denominatorDigits)) - 1
+	if maxDenominator < 1 {
+		maxDenominator = 1
+	}
+	bestNumerator, bestDenominator := 0, 1
+	bestError := math.Inf(1)
+	for d := 1; d <= maxDenominator; d++ {
+		n := math.Round(value * float64(d))
+		if e := math.Abs(value - n/float64(d)); e < bestError-1e-12 {
+			bestNumerator, bestDenominator, bestError = int(n), d, e
+		}
+	}
+	if hasWhole && bestNumerator == bestDenominator {
+		return whole + 1, 0, bestDenominator
+	}
+	return whole, bestNumerator, bestDenominator
+}
+
+// scientific returns mantissa and exponent of value. Engineering notation such as ##0.0E+0 has exponents of multiples of the integer placeholders.
+func scientific(value float64, intPlaceholders string, decimals int) (float64, int) {
+	if value == 0 {
+		return 0, 0
+	}
+	intDigits := len(intPlaceholders)
+	if intDigits == 0 {
+		intDigits = 1
+	}
+	engineering := intDigits > 1 && intPlaceholders[0] == '#'
+
+	exponent := int(math.Floor(math.Log10(value)))
+	step := 1
+	if engineering {
+		step = intDigits
+		exponent -= ((exponent % step) + step) % step
+	} else {
+		exponent -= intDigits - 1
+	}
+	mantissa := roundHalfAwayFromZero(value/math.Pow10(exponent), decimals)
+	if limit := math.Pow10(intDigits); !engineering && mantissa >= limit || engineering && mantissa >= math.Pow10(step) {
+		exponent += step
+		mantissa = roundHalfAwayFromZero(value/math.Pow10(exponent), decimals)
+	}
+	return mantissa, exponent
+}
+
+// serialToTime returns date and time of serial number, rounded to milliseconds.
+func serialToTime(serial float64) time.Time {
+	days := math.Floor(serial)
+	millis := math.Round((serial - days) * 86400000)
+	return excelEpoch.AddDate(0, 0, int(days)).Add(time.Duration(millis) * time.Millisecond)
+}
+
+// formatTime formats t with date and time tokens of section. serial is used for elapsed time such as [h].
+func (s formatSection) formatTime(t time.Time, serial float64) string {
+	// Round to the smallest unit displayed.
+	fractionDigits := 0
+	hasAMPM := false
+	for _, token := range s.tokens {
+		if token.kind == formatDate && strings.HasPrefix(token.text, ".") {
+			fractionDigits = len(token.text) - 1
+		}
+		if token.kind == formatAMPM {
+			hasAMPM = true
+		}
+	}
+	unit := time.Second / time.Duration(math.Pow10(fractionDigits))
+	t = t.Round(unit)
+	serial = math.Round(serial*86400*math.Pow10(fractionDigits)) / math.Pow10(fractionDigits) / 86400
+
+	var b strings.Builder
+	for _, token := range s.tokens {
+		switch token.kind {
+		case formatLiteral, formatComma, formatDecimalPoint, formatSlash:
+			b.WriteString(token.text)
+		case formatDigit:
+			b.WriteString(strings.Trim(token.text, "#?"))
+		case formatAMPM:
+			b.WriteString(ampm(token.text, t.Hour()))
+		case formatDate:
+			b.WriteString(formatDateToken(token.text, t, serial, hasAMPM))
+		}
+	}
+	return b.String()
+}
+
+func ampm(token string, hour int) string {
+	am := hour < 12
+	if len(token) == 3 {
+		// A/P
+		if am {
+			return token[:1]
+		}
+		return token[2:]
+	}
+	if am {
+		return token[:2]
+	}
+	return token[3:]
+}
+
+func formatDateToken(token string, t time.Time, serial float64, twelveHour bool) string {
+	pad := func(v int, width int) string {
+		s := strconv.Itoa(v)
+		for len(s) < width {
+			s = "0" + s
+		}
+		return s
+	}
+
+	switch {
+	case strings.HasPrefix(token, "["):
+		// Elapsed time
+		unit := map[byte]float64{'h': 24, 'm': 1440, 's': 86400}[token[1]]
+		return pad(int(math.Floor(serial*unit+1e-9)), len(token)-2)
+	case strings.HasPrefix(token, "."):
+		digits := len(token) - 1
+		return "." + pad(t.Nanosecond()/int(math.Pow10(9-digits)), digits)
+	}
+
+	n := len(token)
+	switch token[0] {
+	case 'y':
+		if n <= 2 {
+			return pad(t.Year()%100, 2)
+		}
+		return pad(t.Year(), 4)
+	case 'm':
+		switch {
+		case n >= 5:
+			return t.Month().String()[:1]
+		case n == 4:
+			return t.Month().String()
+		case n == 3:
+			return t.Month().String()[:3]
+		}
+		return pad(int(t.Month()), n)
+	case 'd':
+		switch {
+		case n >= 4:
+			return t.Weekday().String()
+		case n == 3:
+			return t.Weekday().String()[:3]
+		}
+		return pad(t.Day(), n)
+	case 'h':
+		hour := t.Hour()
+		if twelveHour {
+			hour %= 12
+			if hour == 0 {
+				hour = 12
+			}
+		}
+		return pad(hour, minInt(n, 2))
+	case 'n':
+		return pad(t.Minute(), minInt(n, 2))
+	case 's':
+		return pad(t.Second(), minInt(n, 2))
+	}
+	return token
+}
+
+// numberFormatTypeOf returns number format type of spreadsheet, such as NUMBER, PERCENT or DATE, matching pattern.
+func numberFormatTypeOf(pattern string) string {
+	s := parseFormatSections(pattern)[0]
+	switch {
+	case s.isTextOnly():
+		return "TEXT"
+	case s.date:
+		hasDate, hasTime := false, false
+		for _, token := range s.tokens {
+			if token.kind != formatDate {
+				continue
+			}
+			switch token.text[0] {
+			case 'y', 'm', 'd':
+				hasDate = true
+			default:
+				hasTime = true
+			}
+		}
+		switch {
+		case hasDate && hasTime:
+			return "DATE_TIME"
+		case hasDate:
+			return "DATE"
+		}
+		return "TIME"
+	case s.percent > 0:
+		return "PERCENT"
+	case s.hasExponent():
+		return "SCIENTIFIC"
+	}
+	return "NUMBER"
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,149 @@
+package herschel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		value   float64
+		pattern string
+		want    string
+	}{
+		{1234.5, "0", "1235"},
+		{1234.5, "0.00", "1234.50"},
+		{1234567.891, "#,##0.00", "1,234,567.89"},
+		{1234, "#,###", "1,234"},
+		{0.25, "#.00", ".25"},
+		{0.2530, "#.00%", "25.30%"},
+		{0.5, "0%", "50%"},
+		{-1234.5, "#,##0.00", "-1,234.50"},
+		{-1234.5, "#,##0.00;(#,##0.00)", "(1,234.50)"},
+		{12.3, "$0.0", "$12.3"},
+		{12, "0\" pcs\"", "12 pcs"},
+		{1.5, "0.0#", "1.5"},
+		{1.567, "0.0#", "1.57"},
+		{7, "000", "007"},
+		{0, "#", ""},
+		{0.5, "0.0?", "0.5 "},
+		{5551234567, "(###) ###-####", "(555) 123-4567"},
+		{1234567, "#,##0,", "1,235"},
+		{1234567, "0.0,,\"M\"", "1.2M"},
+		{12345, "0.00E+00", "1.23E+04"},
+		{0.00012, "0.0E+0", "1.2E-4"},
+		{0.00012, "0.0E-0", "1.2E-4"},
+		{12345, "0.0E-0", "1.2E4"},
+		{12345, "##0.0E+0", "12.3E+3"},
+		{1234567, "##0.0E+0", "1.2E+6"},
+		{0, "0.00E+00", "0.00E+00"},
+		{1.25, "# ?/?", "1 1/4"},
+		{0.3333, "?/?", "1/3"},
+		{2, "# ?/?", "2    "},
+		{1.5, "# ??/100", "1 50/100"},
+		{3.14159, "# ???/???", "3  16/113"},
+		{1234.5, "[$€-407]#,##0.00", "€1,234.50"},
+		{1234.5, "#,##0.00 [$EUR]", "1,234.50 EUR"},
+		{-5, "0;[Red]-0", "-5"},
+		{0, "0.00;-0.00;\"zero\"", "zero"},
+		{-0.001, "0.00", "0.00"},
+		{150, "[>100]\"high\";[<=100]\"low\"", "high"},
+		{50, "[>100]\"high\";[<=100]\"low\"", "low"},
+		{1.5, "0.0_);(0.0)", "1.5 "},
+		{12, "*-0", "12"},
+		{45291, "yyyy-mm-dd", "2023-12-31"},
+	}
+	for _, tt := range tests {
+		if got := formatNumber(tt.value, tt.pattern); got != tt.want {
+			t.Errorf("formatNumber(%v, %q) = %q, want %q", tt.value, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestNumberFormatTypeOf(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"#,##0.00", "NUMBER"},
+		{"0.00%", "PERCENT"},
+		{"0.00E+00", "SCIENTIFIC"},
+		{"yyyy-mm-dd", "DATE"},
+		{"m/d/yyyy h:mm", "DATE_TIME"},
+		{"hh:mm:ss", "TIME"},
+		{"[h]:mm:ss", "TIME"},
+		{"@", "TEXT"},
+		{"[Red]0.00", "NUMBER"},
+		{"0\" days\"", "NUMBER"},
+		{"mmm-yy", "DATE"},
+	}
+	for _, tt := range tests {
+		if got := numberFormatTypeOf(tt.pattern); got != tt.want {
+			t.Errorf("numberFormatTypeOf(%q) = %s, want %s", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		pattern string
+		want    string
+	}{
+		{"apple", "0.00;-0.00;0;\"[\"@\"]\"", "[apple]"},
+		{"apple", "@\" pcs\"", "apple pcs"},
+		{"apple", "0.00", "apple"},
+		{true, "0.00", "TRUE"},
+		{nil, "0.00", ""},
+		{0.1 + 0.2, "", "0.3"},
+		{0.1 + 0.2, "General", "0.3"},
+		{12, "", "12"},
+		{45291.5, "yyyy-mm-dd hh:mm:ss", "2023-12-31 12:00:00"},
+		{45291.75, "m/d/yy h:mm AM/PM", "12/31/23 6:00 PM"},
+		{45291.25, "h:mm a/p", "6:00 a"},
+		{45291, "dddd, mmmm d, yyyy", "Sunday, December 31, 2023"},
+		{45291, "ddd mmm dd", "Sun Dec 31"},
+		{45291, "mmmmm", "D"},
+		{1.5, "[h]:mm", "36:00"},
+		{0.0625, "[mm]:ss", "90:00"},
+		{0.5, "[s]", "43200"},
+		{0.000011574, "hh:mm:ss.000", "00:00:01.000"},
+		{0.99999999, "hh:mm:ss", "00:00:00"},
+		{time.Date(2024, 2, 29, 13, 4, 5, 0, time.UTC), "yyyy/mm/dd hh:mm:ss", "2024/02/29 13:04:05"},
+		{time.Date(2024, 2, 29, 13, 4, 5, 0, time.UTC), "0.00", "45351.54"},
+		{time.Date(2024, 2, 29, 13, 4, 5, 0, time.UTC), "", "2024-02-29 13:04:05"},
+		{1e308, "0.00%", "#NUM!"},
+		{1e308, "0%", "#NUM!"},
+		{1e308, "%0.0E+", "#NUM!"},
+		{-1e308, "0.00%;(0.00%)", "#NUM!"},
+		{1e306, "0%%", "#NUM!"},
+		{7, "@", "7"},
+		{-7.5, "@", "-7.5"},
+		{7, "\"n=\"@", "n=7"},
+	}
+	for _, tt := range tests {
+		if got := formatValue(tt.value, tt.pattern); got != tt.want {
+			t.Errorf("formatValue(%v, %q) = %q, want %q", tt.value, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestFormatValueWithUnusualPatterns(t *testing.T) {
+	patterns := []string{
+		".#/#", "0.0/0", "#.##/##", "0/0E+0", "#/#E+0", "0E+0/0", "E+0/?", "0/E+,?",
+		"%?_E-/#", "%0.0E+", "0.00%", "%%%%", "/", "//", "#/", "/#", "0/0/0", ".", ",", "E+", "0E+", "[>1]", ";;;", "\\", "_", "*",
+	}
+	values := []interface{}{0.0, 1.5, -2.25, 1234.5678, 1e20, 1e-20, 1e308, -1e308, "text", true}
+	for _, pattern := range patterns {
+		for _, value := range values {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Errorf("formatValue(%v, %q) panicked: %v", value, pattern, r)
+					}
+				}()
+				formatValue(value, pattern)
+			}()
+		}
+	}
+}
@@ -5,33 +5,83 @@ import (
 )
 
 func getSheetTitles(client Client, spreadsheetID string) ([]string, error) {
-	spreadsheet, err := client.service.Spreadsheets.Get(spreadsheetID).Do()
+	properties, err := getSheetProperties(client, spreadsheetID)
 	if err != nil {
 		return nil, err
 	}
 
 	sheetTitles := []string{}
 
-	for _, sheet := range spreadsheet.Sheets {
-		sheetTitles = append(sheetTitles, sheet.Properties.Title)
+	for _, p := range properties {
+		sheetTitles = append(sheetTitles, p.Title)
 	}
 	return sheetTitles, nil
 }
 
+func getSheetProperties(client Client, spreadsheetID string) ([]*sheets.SheetProperties, error) {
+	s, err := client.spreadsheets()
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := s.Get(spreadsheetID).Fields("sheets.properties").Do()
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	properties := []*sheets.SheetProperties{}
+	for _, sheet := range spreadsheet.Sheets {
+		properties = append(properties, sheet.Properties)
+	}
+	client.cache.store(spreadsheetID, properties)
+	return properties, nil
+}
+
 func getSheetID(client Client, spreadsheetID string, sheetName string) (int64, bool, error) {
-	spreadsheet, err := client.service.Spreadsheets.Get(spreadsheetID).Do()
+	if id, cached := client.cache.sheetID(spreadsheetID, sheetName); cached {
+		return id, true, nil
+	}
+
+	s, err := client.spreadsheets()
 	if err != nil {
 		return 0, false, err
 	}
+	spreadsheet, err := s.Get(spreadsheetID).Fields("sheets.properties(sheetId,title)").Do()
+	if err != nil {
+		return 0, false, wrapAPIError(err)
+	}
 
+	properties := []*sheets.SheetProperties{}
 	for _, sheet := range spreadsheet.Sheets {
-		if sheet.Properties.Title == sheetName {
-			return sheet.Properties.SheetId, true, nil
+		properties = append(properties, sheet.Properties)
+	}
+	client.cache.store(spreadsheetID, properties)
+
+	for _, p := range properties {
+		if p.Title == sheetName {
+			return p.SheetId, true, nil
 		}
 	}
 	return 0, false, nil
 }
 
+func getProtectedRanges(client Client, spreadsheetID string, sheetID int64) ([]*sheets.ProtectedRange, error) {
+	s, err := client.spreadsheets()
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := s.Get(spreadsheetID).Fields("sheets(properties(sheetId),protectedRanges)").Do()
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.SheetId == sheetID {
+			return sheet.ProtectedRanges, nil
+		}
+	}
+	return nil, nil
+}
+
 func addSheet(client Client, spreadsheetID string, title string) error {
 	req := sheets.Request{
 		AddSheet: &sheets.AddSheetRequest{
@@ -41,9 +91,49 @@ func addSheet(client Client, spreadsheetID string, title string) error {
 		},
 	}
 
+	defer client.cache.invalidate(spreadsheetID)
+	return client.batchUpdate(spreadsheetID, []*sheets.Request{&req})
+}
+
+func duplicateSheet(client Client, spreadsheetID string, sheetID int64, newTitle string, index int) error {
+	req := sheets.Request{
+		DuplicateSheet: &sheets.DuplicateSheetRequest{
+			SourceSheetId:   sheetID,
+			NewSheetName:    newTitle,
+			ForceSendFields: []string{"SourceSheetId"},
+		},
+	}
+	if index >= 0 {
+		req.DuplicateSheet.InsertSheetIndex = int64(index)
+		req.DuplicateSheet.ForceSendFields = append(req.DuplicateSheet.ForceSendFields, "InsertSheetIndex")
+	}
+
+	defer client.cache.invalidate(spreadsheetID)
 	return client.batchUpdate(spreadsheetID, []*sheets.Request{&req})
 }
 
+func copySheetTo(client Client, srcSpreadsheetID string, sheetID int64, dstSpreadsheetID string, newTitle string) error {
+	s, err := client.spreadsheets()
+	if err != nil {
+		return err
+	}
+	properties, err := s.Sheets.CopyTo(srcSpreadsheetID, sheetID, &sheets.CopySheetToAnotherSpreadsheetRequest{
+		DestinationSpreadsheetId: dstSpreadsheetID,
+	}).Do()
+	if err != nil {
+		return wrapAPIError(err)
+	}
+	client.cache.invalidate(dstSpreadsheetID)
+	if len(newTitle) == 0 || properties.Title == newTitle {
+		return nil
+	}
+
+	// Copied sheet is named like "Copy of ..." by the api.
+	return client.batchUpdate(dstSpreadsheetID, []*sheets.Request{
+		updateSheetPropertiesRequest(properties.SheetId, SheetProperties{Title: newTitle}, []SheetProperty{SheetPropertyTitle}),
+	})
+}
+
 func deleteSheetByID(client Client, spreadsheetID string, sheetID int64) error {
 	req := sheets.Request{
 		DeleteSheet: &sheets.DeleteSheetRequest{
@@ -51,6 +141,7 @@ func deleteSheetByID(client Client, spreadsheetID string, sheetID int64) error {
 		},
 	}
 
+	defer client.cache.invalidate(spreadsheetID)
 	return client.batchUpdate(spreadsheetID, []*sheets.Request{&req})
 }
 
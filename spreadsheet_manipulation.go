@@ -6,11 +6,15 @@ import (
 
 // CreateNewSpreadsheet creates new spreadsheet
 func (c *Client) CreateNewSpreadsheet(title string) (string, error) {
-	resp, err := c.service.Spreadsheets.Create(&sheets.Spreadsheet{Properties: &sheets.SpreadsheetProperties{
+	s, err := c.spreadsheets()
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.Create(&sheets.Spreadsheet{Properties: &sheets.SpreadsheetProperties{
 		Title: title,
 	}}).Do()
 	if err != nil {
-		return "", err
+		return "", wrapAPIError(err)
 	}
 	return resp.SpreadsheetId, nil
 }
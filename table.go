@@ -5,18 +5,43 @@ import (
 	"image/color"
 	"log"
 	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
 )
 
 // Table represents 2 dimension cells.
 type Table struct {
-	cols              int
-	rows              int
-	values            map[int]map[int]interface{}
-	backgroundColors  map[int]map[int]color.Color
-	numberFormats     map[int]map[int]string
-	numberFormatTypes map[int]map[int]string
+	cols     int
+	rows     int
+	growable bool
+	// cells holds values in row-major order. A row is nil until a value is written, and may be shorter than cols.
+	cells [][]interface{}
+	// styles holds formats of cells sparsely. A row is nil until a format is set.
+	styles []map[int]cellStyle
+	// columnWidths holds widths of columns in pixels. Columns with the default width are omitted.
+	columnWidths      map[int]int
 	FrozenRowCount    int64
 	FrozenColumnCount int64
+	// ProtectedRowCount is the number of header rows protected by WriteTable.
+	// Header protections added by previous writes are replaced.
+	ProtectedRowCount int64
+	// HeaderProtection configures the protection of header rows.
+	HeaderProtection ProtectionOptions
+	// RemoveHeaderProtection removes header protections added by previous writes when ProtectedRowCount is 0.
+	// It costs a request to read protected ranges, so it is off by default.
+	RemoveHeaderProtection bool
+}
+
+// cellStyle represents formats of a cell.
+type cellStyle struct {
+	backgroundColor  color.Color
+	numberFormat     string
+	numberFormatType string
+}
+
+func (s cellStyle) isZero() bool {
+	return (s.backgroundColor == nil || s.backgroundColor == color.Transparent) && len(s.numberFormat) == 0 && len(s.numberFormatType) == 0
 }
 
 func (t Table) String() string {
@@ -25,27 +50,87 @@ func (t Table) String() string {
 
 // NewTable returns instance of Table.
 func NewTable(rows int, cols int) *Table {
-	instance := &Table{cols: cols, rows: rows}
+	if rows < 0 {
+		rows = 0
+	}
+	return &Table{
+		cols:   cols,
+		rows:   rows,
+		cells:  make([][]interface{}, rows),
+		styles: make([]map[int]cellStyle, rows),
+	}
+}
+
+// NewGrowableTable returns instance of Table which grows when cells beyond its size are written.
+func NewGrowableTable(rows int, cols int) *Table {
+	t := NewTable(rows, cols)
+	t.growable = true
+	return t
+}
+
+// SetGrowable sets whether table grows when cells beyond its size are written.
+func (t *Table) SetGrowable(growable bool) {
+	t.growable = growable
+}
+
+// IsGrowable reports whether table grows when cells beyond its size are written.
+func (t *Table) IsGrowable() bool {
+	return t.growable
+}
+
+// expand grows table to have at least rows x cols.
+func (t *Table) expand(rows int, cols int) {
+	if rows > t.rows {
+		t.cells = append(t.cells, make([][]interface{}, rows-t.rows)...)
+		t.styles = append(t.styles, make([]map[int]cellStyle, rows-t.rows)...)
+		t.rows = rows
+	}
+	if cols > t.cols {
+		t.cols = cols
+	}
+}
+
+// rowForWrite returns slice of row with length of at least col + 1.
+func (t *Table) rowForWrite(row int, col int) []interface{} {
+	r := t.cells[row]
+	if col < len(r) {
+		return r
+	}
+	size := t.cols
+	if col >= size {
+		size = col + 1
+	}
+	if size <= cap(r) {
+		r = r[:size]
+	} else {
+		extended := make([]interface{}, size)
+		copy(extended, r)
+		r = extended
+	}
+	t.cells[row] = r
+	return r
+}
 
-	values := map[int]map[int]interface{}{}
-	backgroundColors := map[int]map[int]color.Color{}
-	numberFormats := map[int]map[int]string{}
-	numberFormatTypes := map[int]map[int]string{}
+func (t *Table) setCell(row int, col int, value interface{}) {
+	t.rowForWrite(row, col)[col] = value
+}
 
-	for i := 0; i < rows; i++ {
-		values[i] = map[int]interface{}{}
-		backgroundColors[i] = map[int]color.Color{}
-		numberFormats[i] = map[int]string{}
-		numberFormatTypes[i] = map[int]string{}
+func (t *Table) getStyle(row int, col int) cellStyle {
+	if row < 0 || row >= len(t.styles) {
+		return cellStyle{}
 	}
+	return t.styles[row][col]
+}
 
-	instance.values = values
-	instance.backgroundColors = backgroundColors
-	instance.numberFormats = numberFormats
-	instance.numberFormatTypes = numberFormatTypes
-	instance.FrozenRowCount = 0
-	instance.FrozenColumnCount = 0
-	return instance
+func (t *Table) setStyle(row int, col int, style cellStyle) {
+	if style.isZero() {
+		delete(t.styles[row], col)
+		return
+	}
+	if t.styles[row] == nil {
+		t.styles[row] = map[int]cellStyle{}
+	}
+	t.styles[row][col] = style
 }
 
 // GetRows returns number of rows
@@ -58,27 +143,71 @@ func (t *Table) GetCols() int {
 	return t.cols
 }
 
-// PutValue updates value of cell.
+// PutValue updates value of cell. It panics when cell is out of table, unless table is growable.
 func (t *Table) PutValue(row int, col int, value interface{}) {
+	if t.growable {
+		t.expand(row+1, col+1)
+	}
 	if row >= t.rows {
 		panic(fmt.Sprintf("row out of order: %d in %d", row, t.rows))
 	}
 	if col >= t.cols {
 		panic(fmt.Sprintf("col out of order: %d in %d", col, t.cols))
 	}
-	t.values[row][col] = value
+	t.setCell(row, col, value)
+}
+
+// SetValue updates value of cell. It returns ErrOutOfRange when cell is out of table, unless table is growable.
+func (t *Table) SetValue(row int, col int, value interface{}) error {
+	if err := t.ensureCell(row, col); err != nil {
+		return err
+	}
+	t.setCell(row, col, value)
+	return nil
+}
+
+// SetRow sets values of cells at row. It returns ErrOutOfRange without changing any cells when values don't fit in table, unless table is growable.
+func (t *Table) SetRow(row int, values ...interface{}) error {
+	lastCol := len(values) - 1
+	if lastCol < 0 {
+		lastCol = 0
+	}
+	if err := t.ensureCell(row, lastCol); err != nil {
+		return err
+	}
+	for col, v := range values {
+		t.setCell(row, col, v)
+	}
+	return nil
+}
+
+// ensureCell grows growable table to contain the cell, and returns ErrOutOfRange when the cell is out of table.
+func (t *Table) ensureCell(row int, col int) error {
+	if row < 0 || col < 0 {
+		return errors.Wrapf(ErrOutOfRange, "(%d, %d)", row, col)
+	}
+	if t.growable {
+		t.expand(row+1, col+1)
+	}
+	if row >= t.rows || col >= t.cols {
+		return errors.Wrapf(ErrOutOfRange, "(%d, %d) in %d x %d table", row, col, t.rows, t.cols)
+	}
+	return nil
 }
 
 // GetValue returns value of cell.
 func (t *Table) GetValue(row int, col int) interface{} {
-	return t.values[row][col]
+	if row < 0 || row >= len(t.cells) || col < 0 || col >= len(t.cells[row]) {
+		return nil
+	}
+	return t.cells[row][col]
 }
 
 // GetValuesAtRow returns a slice containing value of cells at row
 func (t *Table) GetValuesAtRow(row int) []interface{} {
-	cells := []interface{}{}
-	for i := 0; i < t.cols; i++ {
-		cells = append(cells, t.GetValue(row, i))
+	cells := make([]interface{}, t.cols)
+	if row >= 0 && row < len(t.cells) {
+		copy(cells, t.cells[row])
 	}
 	return cells
 }
@@ -122,24 +251,25 @@ L:
 
 // Values returns slice of cell values
 func (t *Table) Values() [][]interface{} {
-	values := [][]interface{}{}
+	values := make([][]interface{}, t.rows)
 	for row := 0; row < t.rows; row++ {
-		rowValues := []interface{}{}
-		for col := 0; col < t.cols; col++ {
-			rowValues = append(rowValues, t.GetValue(row, col))
-		}
-		values = append(values, rowValues)
+		values[row] = t.GetValuesAtRow(row)
 	}
 	return values
 }
 
 // SetBackgroundColor sets background color of cell at (row, col)
 func (t *Table) SetBackgroundColor(row int, col int, c color.Color) {
-	t.backgroundColors[row][col] = c
+	if t.growable {
+		t.expand(row+1, col+1)
+	}
+	style := t.getStyle(row, col)
+	style.backgroundColor = c
+	t.setStyle(row, col, style)
 }
 
 func (t *Table) getBackgroundColor(row int, col int) color.Color {
-	if c, exists := t.backgroundColors[row][col]; exists {
+	if c := t.getStyle(row, col).backgroundColor; c != nil {
 		return c
 	}
 	return color.Transparent
@@ -147,26 +277,62 @@ func (t *Table) getBackgroundColor(row int, col int) color.Color {
 
 // SetNumberFormatPattern sets number format pettern of cell at (row, col)
 func (t *Table) SetNumberFormatPattern(row int, col int, pattern string) {
-	t.numberFormats[row][col] = pattern
+	if t.growable {
+		t.expand(row+1, col+1)
+	}
+	style := t.getStyle(row, col)
+	style.numberFormat = pattern
+	t.setStyle(row, col, style)
 }
 
 func (t *Table) getNumberFormatPattern(row int, col int) string {
-	if f, exists := t.numberFormats[row][col]; exists {
-		return f
-	}
-	return ""
+	return t.getStyle(row, col).numberFormat
 }
 
 // SetNumberFormatType sets number format type of cell at (row, col)
 func (t *Table) SetNumberFormatType(row int, col int, formatType string) {
-	t.numberFormatTypes[row][col] = formatType
+	if t.growable {
+		t.expand(row+1, col+1)
+	}
+	style := t.getStyle(row, col)
+	style.numberFormatType = formatType
+	t.setStyle(row, col, style)
 }
 
 func (t *Table) getNumberFormatType(row int, col int) string {
-	if f, exists := t.numberFormatTypes[row][col]; exists {
-		return f
+	return t.getStyle(row, col).numberFormatType
+}
+
+// SetColumnWidth sets width of column in pixels. Zero resets the column to the default width.
+func (t *Table) SetColumnWidth(col int, pixels int) {
+	if t.growable {
+		t.expand(0, col+1)
+	}
+	if pixels <= 0 {
+		delete(t.columnWidths, col)
+		return
 	}
-	return ""
+	if t.columnWidths == nil {
+		t.columnWidths = map[int]int{}
+	}
+	t.columnWidths[col] = pixels
+}
+
+// GetColumnWidth returns width of column in pixels, or zero for the default width.
+func (t *Table) GetColumnWidth(col int) int {
+	return t.columnWidths[col]
+}
+
+// widthCols returns sorted indices of columns with widths.
+func (t *Table) widthCols() []int {
+	cols := make([]int, 0, len(t.columnWidths))
+	for col := range t.columnWidths {
+		if col < t.cols {
+			cols = append(cols, col)
+		}
+	}
+	sort.Ints(cols)
+	return cols
 }
 
 // PutCommaSeparatedInt64 set value of cell at (row, col) as comma separated integer.
@@ -176,10 +342,20 @@ func (t *Table) PutCommaSeparatedInt64(row int, col int, value int64) {
 }
 
 func (t *Table) clearCell(row int, col int) {
-	delete(t.values[row], col)
-	delete(t.backgroundColors[row], col)
-	delete(t.numberFormats[row], col)
-	delete(t.numberFormatTypes[row], col)
+	if col < len(t.cells[row]) {
+		t.cells[row][col] = nil
+	}
+	delete(t.styles[row], col)
+}
+
+// styledCols returns cols with formats at row in ascending order.
+func (t *Table) styledCols(row int) []int {
+	cols := make([]int, 0, len(t.styles[row]))
+	for col := range t.styles[row] {
+		cols = append(cols, col)
+	}
+	sort.Ints(cols)
+	return cols
 }
 
 // ToMap creates map from table. First column value as key, second column value as value.
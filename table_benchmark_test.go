@@ -0,0 +1,179 @@
+package herschel
+
+import (
+	"image/color"
+	"testing"
+)
+
+// legacyTable reproduces the previous map based storage of Table to compare performance.
+type legacyTable struct {
+	rows             int
+	cols             int
+	values           map[int]map[int]interface{}
+	backgroundColors map[int]map[int]color.Color
+	numberFormats    map[int]map[int]string
+}
+
+func newLegacyTable(rows int, cols int) *legacyTable {
+	t := &legacyTable{rows: rows, cols: cols,
+		values:           map[int]map[int]interface{}{},
+		backgroundColors: map[int]map[int]color.Color{},
+		numberFormats:    map[int]map[int]string{},
+	}
+	for i := 0; i < rows; i++ {
+		t.values[i] = map[int]interface{}{}
+		t.backgroundColors[i] = map[int]color.Color{}
+		t.numberFormats[i] = map[int]string{}
+	}
+	return t
+}
+
+func (t *legacyTable) copyCell(targetRow int, targetCol int, s *legacyTable, sourceRow int, sourceCol int) {
+	t.values[targetRow][targetCol] = s.values[sourceRow][sourceCol]
+	t.backgroundColors[targetRow][targetCol] = s.backgroundColors[sourceRow][sourceCol]
+	t.numberFormats[targetRow][targetCol] = s.numberFormats[sourceRow][sourceCol]
+}
+
+func (t *legacyTable) rowValues(row int) []interface{} {
+	cells := []interface{}{}
+	for col := 0; col < t.cols; col++ {
+		cells = append(cells, t.values[row][col])
+	}
+	return cells
+}
+
+func (t *legacyTable) appendTableAtBottom(a *legacyTable) *legacyTable {
+	n := newLegacyTable(t.rows+a.rows, t.cols)
+	for row := 0; row < t.rows; row++ {
+		for col := 0; col < t.cols; col++ {
+			n.copyCell(row, col, t, row, col)
+		}
+	}
+	for row := 0; row < a.rows; row++ {
+		for col := 0; col < a.cols; col++ {
+			n.copyCell(row+t.rows, col, a, row, col)
+		}
+	}
+	return n
+}
+
+func (t *legacyTable) subTableByFilteringRows(f func(values []interface{}) bool) *legacyTable {
+	s := newLegacyTable(0, t.cols)
+	for i := 0; i < t.rows; i++ {
+		if f(t.rowValues(i)) {
+			st := newLegacyTable(1, t.cols)
+			for col := 0; col < t.cols; col++ {
+				st.copyCell(0, col, t, i, col)
+			}
+			s = s.appendTableAtBottom(st)
+		}
+	}
+	return s
+}
+
+func (t *legacyTable) insertColAtIndex(index int) {
+	t.cols++
+	for col := t.cols - 1; col > index; col-- {
+		for row := 0; row < t.rows; row++ {
+			t.copyCell(row, col, t, row, col-1)
+		}
+	}
+	for row := 0; row < t.rows; row++ {
+		delete(t.values[row], index)
+		delete(t.backgroundColors[row], index)
+		delete(t.numberFormats[row], index)
+	}
+}
+
+const (
+	benchmarkRows = 10000
+	benchmarkCols = 10
+)
+
+func everyTenthRow(values []interface{}) bool {
+	return values[0].(int)%10 == 0
+}
+
+func newBenchmarkTable() *Table {
+	t := NewTable(benchmarkRows, benchmarkCols)
+	for row := 0; row < benchmarkRows; row++ {
+		for col := 0; col < benchmarkCols; col++ {
+			t.PutValue(row, col, row)
+		}
+	}
+	return t
+}
+
+func newBenchmarkLegacyTable() *legacyTable {
+	t := newLegacyTable(benchmarkRows, benchmarkCols)
+	for row := 0; row < benchmarkRows; row++ {
+		for col := 0; col < benchmarkCols; col++ {
+			t.values[row][col] = row
+		}
+	}
+	return t
+}
+
+func BenchmarkFill(b *testing.B) {
+	b.Run("Legacy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			newBenchmarkLegacyTable()
+		}
+	})
+	b.Run("Table", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			newBenchmarkTable()
+		}
+	})
+}
+
+func BenchmarkSubTableByFilteringRows(b *testing.B) {
+	b.Run("Legacy", func(b *testing.B) {
+		t := newBenchmarkLegacyTable()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			t.subTableByFilteringRows(everyTenthRow)
+		}
+	})
+	b.Run("Table", func(b *testing.B) {
+		t := newBenchmarkTable()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			t.SubTableByFilteringRows(everyTenthRow)
+		}
+	})
+}
+
+func BenchmarkInsertColAtIndex(b *testing.B) {
+	b.Run("Legacy", func(b *testing.B) {
+		t := newBenchmarkLegacyTable()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			t.insertColAtIndex(0)
+		}
+	})
+	b.Run("Table", func(b *testing.B) {
+		t := newBenchmarkTable()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			t.InsertColAtIndex(0)
+		}
+	})
+}
+
+func BenchmarkAppendTableAtBottom(b *testing.B) {
+	b.Run("Legacy", func(b *testing.B) {
+		t := newBenchmarkLegacyTable()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			t.appendTableAtBottom(t)
+		}
+	})
+	b.Run("Table", func(b *testing.B) {
+		t := newBenchmarkTable()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			t.AppendTableAtBottom(t)
+		}
+	})
+}
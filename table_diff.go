@@ -0,0 +1,195 @@
+package herschel
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CellDiff represents a changed cell between two tables.
+type CellDiff struct {
+	// Row is the index of row in the original table.
+	Row int
+	// OtherRow is the index of row in the other table. It differs from Row when rows are matched by keys.
+	OtherRow int
+	Col      int
+	OldValue interface{}
+	NewValue interface{}
+	// ValueChanged reports whether the value differs.
+	ValueChanged bool
+	// FormatChanged reports whether background color or number format differs.
+	FormatChanged bool
+}
+
+// TableDiff represents differences between two tables.
+type TableDiff struct {
+	// AddedRows are indices of rows in the other table which don't exist in the original table.
+	AddedRows []int
+	// RemovedRows are indices of rows in the original table which don't exist in the other table.
+	RemovedRows []int
+	// AddedCols are indices of cols in the other table beyond the original table.
+	AddedCols []int
+	// RemovedCols are indices of cols in the original table beyond the other table.
+	RemovedCols []int
+	// ChangedCells are cells which exist in both tables with different values or formats.
+	ChangedCells []CellDiff
+}
+
+// IsEmpty reports whether there are no differences.
+func (d TableDiff) IsEmpty() bool {
+	return len(d.AddedRows) == 0 && len(d.RemovedRows) == 0 && len(d.AddedCols) == 0 && len(d.RemovedCols) == 0 && len(d.ChangedCells) == 0
+}
+
+// Diff returns differences from table to other. Rows are compared by position, or matched by values of key columns when keyCols are given.
+// Values are compared as the strings a user would enter, so 1 and "1" are equal.
+func (t *Table) Diff(other *Table, keyCols ...int) TableDiff {
+	d := TableDiff{}
+
+	for col := t.cols; col < other.cols; col++ {
+		d.AddedCols = append(d.AddedCols, col)
+	}
+	for col := other.cols; col < t.cols; col++ {
+		d.RemovedCols = append(d.RemovedCols, col)
+	}
+
+	pairs := [][2]int{}
+	if len(keyCols) == 0 {
+		for row := 0; row < t.rows && row < other.rows; row++ {
+			pairs = append(pairs, [2]int{row, row})
+		}
+		for row := t.rows; row < other.rows; row++ {
+			d.AddedRows = append(d.AddedRows, row)
+		}
+		for row := other.rows; row < t.rows; row++ {
+			d.RemovedRows = append(d.RemovedRows, row)
+		}
+	} else {
+		index := t.rowIndexByKey(keyCols)
+		matched := make([]bool, t.rows)
+		for row := 0; row < other.rows; row++ {
+			key := rowKey(other.GetValuesAtRow(row), keyCols)
+			if rows := index[key]; len(rows) > 0 {
+				pairs = append(pairs, [2]int{rows[0], row})
+				matched[rows[0]] = true
+				index[key] = rows[1:]
+			} else {
+				d.AddedRows = append(d.AddedRows, row)
+			}
+		}
+		for row := 0; row < t.rows; row++ {
+			if !matched[row] {
+				d.RemovedRows = append(d.RemovedRows, row)
+			}
+		}
+	}
+
+	cols := t.cols
+	if other.cols < cols {
+		cols = other.cols
+	}
+	for _, p := range pairs {
+		for col := 0; col < cols; col++ {
+			c := CellDiff{
+				Row:           p[0],
+				OtherRow:      p[1],
+				Col:           col,
+				OldValue:      t.GetValue(p[0], col),
+				NewValue:      other.GetValue(p[1], col),
+				ValueChanged:  !sameCellValue(t, p[0], other, p[1], col),
+				FormatChanged: !sameCellFormat(t, p[0], other, p[1], col),
+			}
+			if c.ValueChanged || c.FormatChanged {
+				d.ChangedCells = append(d.ChangedCells, c)
+			}
+		}
+	}
+	return d
+}
+
+// rowIndexByKey returns indices of rows grouped by values of key columns.
+func (t *Table) rowIndexByKey(keyCols []int) map[string][]int {
+	index := map[string][]int{}
+	for row := 0; row < t.rows; row++ {
+		key := rowKey(t.GetValuesAtRow(row), keyCols)
+		index[key] = append(index[key], row)
+	}
+	return index
+}
+
+// rowKey returns a key of row built from values of key columns.
+func rowKey(values []interface{}, keyCols []int) string {
+	parts := make([]string, len(keyCols))
+	for i, col := range keyCols {
+		if col >= 0 && col < len(values) {
+			parts[i] = userEnteredString(values[col])
+		}
+	}
+	return strings.Join(parts, "\x1f")
+}
+
+func sameCellValue(a *Table, aRow int, b *Table, bRow int, col int) bool {
+	return userEnteredString(a.GetValue(aRow, col)) == userEnteredString(b.GetValue(bRow, col))
+}
+
+func sameCellFormat(a *Table, aRow int, b *Table, bRow int, col int) bool {
+	if !sameBackgroundColor(a.getBackgroundColor(aRow, col), b.getBackgroundColor(bRow, col)) {
+		return false
+	}
+	return a.getNumberFormatPattern(aRow, col) == b.getNumberFormatPattern(bRow, col) &&
+		effectiveNumberFormatType(a, aRow, col) == effectiveNumberFormatType(b, bRow, col)
+}
+
+func sameBackgroundColor(a color.Color, b color.Color) bool {
+	if a == color.Transparent || b == color.Transparent {
+		return a == b
+	}
+	// Alpha is not compared because spreadsheets ignore it.
+	r1, g1, b1, _ := a.RGBA()
+	r2, g2, b2, _ := b.RGBA()
+	return r1>>8 == r2>>8 && g1>>8 == g2>>8 && b1>>8 == b2>>8
+}
+
+func effectiveNumberFormatType(t *Table, row int, col int) string {
+	if len(t.getNumberFormatPattern(row, col)) == 0 {
+		return ""
+	}
+	if formatType := t.getNumberFormatType(row, col); len(formatType) > 0 {
+		return formatType
+	}
+	return "NUMBER"
+}
+
+// userEnteredString returns the string a user would type to enter value.
+func userEnteredString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case Formula:
+		return string(value)
+	case int:
+		return strconv.Itoa(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(value))
+	case time.Time:
+		return userEnteredTime(value)
+	}
+	return fmt.Sprint(v)
+}
+
+// userEnteredTime returns time in a format spreadsheet parses as date or date time.
+func userEnteredTime(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,81 @@
+package herschel
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestTableDiff(t *testing.T) {
+	orig := NewTable(3, 2)
+	orig.PutValuesAtRow(0, "id", "name")
+	orig.PutValuesAtRow(1, "1", "foo")
+	orig.PutValuesAtRow(2, "2", "bar")
+
+	t.Run("Identical", func(t *testing.T) {
+		other := NewTable(3, 2)
+		other.PutValuesAtRow(0, "id", "name")
+		other.PutValuesAtRow(1, 1, "foo")
+		other.PutValuesAtRow(2, 2, "bar")
+		if d := orig.Diff(other); !d.IsEmpty() {
+			t.Errorf("No differences expected, got: %+v", d)
+		}
+	})
+
+	t.Run("Positional", func(t *testing.T) {
+		other := NewTable(4, 3)
+		other.PutValuesAtRow(0, "id", "name", "city")
+		other.PutValuesAtRow(1, "1", "foo")
+		other.PutValuesAtRow(2, "2", "baz")
+		other.PutValuesAtRow(3, "3", "qux")
+		other.SetBackgroundColor(1, 0, color.Black)
+
+		d := orig.Diff(other)
+		if !reflect.DeepEqual(d.AddedRows, []int{3}) || len(d.RemovedRows) != 0 {
+			t.Errorf("Unexpected rows: added %v, removed %v", d.AddedRows, d.RemovedRows)
+		}
+		if !reflect.DeepEqual(d.AddedCols, []int{2}) || len(d.RemovedCols) != 0 {
+			t.Errorf("Unexpected cols: added %v, removed %v", d.AddedCols, d.RemovedCols)
+		}
+		want := []CellDiff{
+			{Row: 1, OtherRow: 1, Col: 0, OldValue: "1", NewValue: "1", FormatChanged: true},
+			{Row: 2, OtherRow: 2, Col: 1, OldValue: "bar", NewValue: "baz", ValueChanged: true},
+		}
+		if !reflect.DeepEqual(d.ChangedCells, want) {
+			t.Errorf("ChangedCells = %+v, want %+v", d.ChangedCells, want)
+		}
+	})
+
+	t.Run("Keyed", func(t *testing.T) {
+		other := NewTable(3, 2)
+		other.PutValuesAtRow(0, "id", "name")
+		other.PutValuesAtRow(1, "2", "baz")
+		other.PutValuesAtRow(2, "3", "qux")
+
+		d := orig.Diff(other, 0)
+		if !reflect.DeepEqual(d.AddedRows, []int{2}) {
+			t.Errorf("AddedRows = %v, want [2]", d.AddedRows)
+		}
+		if !reflect.DeepEqual(d.RemovedRows, []int{1}) {
+			t.Errorf("RemovedRows = %v, want [1]", d.RemovedRows)
+		}
+		want := []CellDiff{
+			{Row: 2, OtherRow: 1, Col: 1, OldValue: "bar", NewValue: "baz", ValueChanged: true},
+		}
+		if !reflect.DeepEqual(d.ChangedCells, want) {
+			t.Errorf("ChangedCells = %+v, want %+v", d.ChangedCells, want)
+		}
+	})
+
+	t.Run("MultipleKeyCols", func(t *testing.T) {
+		a := NewTable(2, 3)
+		a.PutValuesAtRow(0, "x", "1", "foo")
+		a.PutValuesAtRow(1, "x", "2", "bar")
+		b := NewTable(2, 3)
+		b.PutValuesAtRow(0, "x", "2", "bar")
+		b.PutValuesAtRow(1, "x", "1", "foo")
+		if d := a.Diff(b, 0, 1); !d.IsEmpty() {
+			t.Errorf("No differences expected, got: %+v", d)
+		}
+	})
+}
@@ -4,29 +4,110 @@ import (
 	"encoding/csv"
 	"io"
 	"strconv"
+
+	"github.com/pkg/errors"
 )
 
+// CSVExportOptions configures ToCSVWithOptions.
+type CSVExportOptions struct {
+	// Delimiter separates fields. Comma is used when zero. Use '\t' for TSV.
+	Delimiter rune
+	// UseCRLF terminates lines with \r\n instead of \n.
+	UseCRLF bool
+	// BOM writes UTF-8 byte order mark first, so that Excel detects the encoding.
+	BOM bool
+	// FloatPrecision is the number of digits after the decimal point of floats.
+	// Zero formats floats with the smallest number of digits necessary to represent the value.
+	FloatPrecision int
+	// ApplyNumberFormat formats numbers with number format pattern of each cell.
+	ApplyNumberFormat bool
+	// RowStart (inclusive) and RowEnd (exclusive) limit rows written. All rows are written when RowEnd is zero.
+	RowStart int
+	RowEnd   int
+}
+
 // ToCSV writes table in csv format
 func (t *Table) ToCSV(w io.Writer) error {
+	return t.ToCSVWithOptions(w, CSVExportOptions{})
+}
+
+// ToCSVWithOptions writes table in csv format configured by opts.
+func (t *Table) ToCSVWithOptions(w io.Writer, opts CSVExportOptions) error {
+	rowEnd := opts.RowEnd
+	if rowEnd == 0 {
+		rowEnd = t.rows
+	}
+	if opts.RowStart < 0 || rowEnd > t.rows || opts.RowStart > rowEnd {
+		return errors.Wrapf(ErrOutOfRange, "rows from %d to %d of table with %d rows", opts.RowStart, rowEnd, t.rows)
+	}
+
+	if opts.BOM {
+		if _, err := io.WriteString(w, "\ufeff"); err != nil {
+			return err
+		}
+	}
+
 	cw := csv.NewWriter(w)
-	defer cw.Flush()
-	for i := 0; i < t.GetRows(); i++ {
+	if opts.Delimiter != 0 {
+		cw.Comma = opts.Delimiter
+	}
+	cw.UseCRLF = opts.UseCRLF
+	for i := opts.RowStart; i < rowEnd; i++ {
 		strValues := []string{}
 		for j := 0; j < t.GetCols(); j++ {
-			s := ""
-			v := t.GetValue(i, j)
-			if v != nil {
-				if strValue, ok := v.(string); ok {
-					s = strValue
-				} else if i, ok := v.(int); ok {
-					s = strconv.Itoa(i)
-				} else if i, ok := v.(int64); ok {
-					s = strconv.FormatInt(i, 10)
-				}
-			}
-			strValues = append(strValues, s)
+			strValues = append(strValues, t.csvString(i, j, opts))
+		}
+		if err := cw.Write(strValues); err != nil {
+			return err
 		}
-		cw.Write(strValues)
 	}
+	cw.Flush()
 	return cw.Error()
 }
+
+func (t *Table) csvString(row int, col int, opts CSVExportOptions) string {
+	v := t.GetValue(row, col)
+	if opts.ApplyNumberFormat && (len(t.getNumberFormatPattern(row, col)) > 0 || len(t.getNumberFormatType(row, col)) > 0) {
+		return t.GetDisplayString(row, col)
+	}
+	if opts.FloatPrecision > 0 {
+		switch f := v.(type) {
+		case float64:
+			return strconv.FormatFloat(f, 'f', opts.FloatPrecision, 64)
+		case float32:
+			return strconv.FormatFloat(float64(f), 'f', opts.FloatPrecision, 32)
+		}
+	}
+	return userEnteredString(v)
+}
+
+// toFloat64 converts numeric value to float64.
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
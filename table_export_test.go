@@ -6,6 +6,9 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 func TestCSVExport(t *testing.T) {
@@ -30,3 +33,54 @@ func TestCSVExport(t *testing.T) {
 		}
 	}
 }
+
+type testStringer struct{}
+
+func (testStringer) String() string { return "stringer" }
+
+func TestCSVExportValueTypes(t *testing.T) {
+	table := NewTable(1, 7)
+	table.PutValuesAtRow(0, 0.25, float32(1.5), true, uint8(7), time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), testStringer{}, nil)
+
+	buf := bytes.NewBufferString("")
+	if err := table.ToCSV(buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "0.25,1.5,TRUE,7,2022-01-02,stringer,\n" {
+		t.Errorf("Unexpected csv: %q", buf.String())
+	}
+}
+
+func TestCSVExportWithOptions(t *testing.T) {
+	table := NewTable(3, 2)
+	table.PutValuesAtRow(0, "name", "price")
+	table.PutValuesAtRow(1, "a", 1234.5)
+	table.PutValuesAtRow(2, "b", 0.126)
+	table.SetNumberFormatPattern(1, 1, "$#,##0.00")
+
+	tests := []struct {
+		name string
+		opts CSVExportOptions
+		want string
+	}{
+		{"TSV", CSVExportOptions{Delimiter: '\t', UseCRLF: true}, "name\tprice\r\na\t1234.5\r\nb\t0.126\r\n"},
+		{"BOM", CSVExportOptions{BOM: true, RowEnd: 1}, "\ufeffname,price\n"},
+		{"FloatPrecision", CSVExportOptions{FloatPrecision: 2, RowStart: 1}, "a,1234.50\nb,0.13\n"},
+		{"NumberFormat", CSVExportOptions{ApplyNumberFormat: true, RowStart: 1, RowEnd: 2}, "a,\"$1,234.50\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.NewBufferString("")
+			if err := table.ToCSVWithOptions(buf, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Unexpected csv: %q", buf.String())
+			}
+		})
+	}
+
+	if err := table.ToCSVWithOptions(bytes.NewBufferString(""), CSVExportOptions{RowStart: 2, RowEnd: 4}); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("ErrOutOfRange expected, got: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package herschel
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// TextStyle is a style of text rendered by Format.
+type TextStyle int
+
+const (
+	// TextStyleBox draws borders of cells with box drawing characters.
+	TextStyleBox TextStyle = iota
+	// TextStyleTab aligns cells with text/tabwriter without borders.
+	TextStyleTab
+)
+
+// FormatOptions configures Format.
+type FormatOptions struct {
+	Style TextStyle
+	// MaxCellWidth truncates cells wider than it. Cells are not truncated when zero.
+	MaxCellWidth int
+	// MaxRows limits the number of rows rendered. All rows are rendered when zero.
+	MaxRows int
+	// Color renders background colors of cells with ANSI escape sequences. It is applied to TextStyleBox only.
+	Color bool
+}
+
+// Format writes table as text for terminals and logs, with column letters and row numbers.
+// Values are formatted with number format patterns.
+func (t *Table) Format(w io.Writer, opts FormatOptions) error {
+	rows := t.rows
+	if opts.MaxRows > 0 && opts.MaxRows < rows {
+		rows = opts.MaxRows
+	}
+
+	// The first row and column hold column letters and row numbers.
+	grid := make([][]string, rows+1)
+	grid[0] = make([]string, t.cols+1)
+	for col := 0; col < t.cols; col++ {
+		grid[0][col+1] = columnName(col)
+	}
+	for row := 0; row < rows; row++ {
+		grid[row+1] = make([]string, t.cols+1)
+		grid[row+1][0] = strconv.Itoa(row + 1)
+		for col := 0; col < t.cols; col++ {
+			grid[row+1][col+1] = truncateText(formatCellText(t.GetDisplayString(row, col)), opts.MaxCellWidth)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	if opts.Style == TextStyleTab {
+		tw := tabwriter.NewWriter(bw, 0, 0, 2, ' ', 0)
+		for _, cells := range grid {
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	} else {
+		t.writeBox(bw, grid, opts)
+	}
+
+	if rows < t.rows {
+		fmt.Fprintf(bw, "... %d more rows\n", t.rows-rows)
+	}
+	return bw.Flush()
+}
+
+func (t *Table) writeBox(w *bufio.Writer, grid [][]string, opts FormatOptions) {
+	widths := make([]int, len(grid[0]))
+	for _, cells := range grid {
+		for i, cell := range cells {
+			if width := textWidth(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	border := func(left, middle, right string) {
+		w.WriteString(left)
+		for i, width := range widths {
+			if i > 0 {
+				w.WriteString(middle)
+			}
+			w.WriteString(strings.Repeat("─", width+2))
+		}
+		w.WriteString(right + "\n")
+	}
+
+	border("┌", "┬", "┐")
+	for r, cells := range grid {
+		if r == 1 {
+			border("├", "┼", "┤")
+		}
+		w.WriteString("│")
+		for i, cell := range cells {
+			padding := strings.Repeat(" ", widths[i]-textWidth(cell))
+			text := " " + cell + padding + " "
+			if r > 0 && (i == 0 || isNumericText(t, r-1, i-1)) {
+				text = " " + padding + cell + " "
+			}
+			if opts.Color && r > 0 && i > 0 {
+				if c := t.getBackgroundColor(r-1, i-1); c != color.Transparent {
+					text = ansiBackground(c) + text + "\x1b[0m"
+				}
+			}
+			w.WriteString(text + "│")
+		}
+		w.WriteString("\n")
+	}
+	border("└", "┴", "┘")
+}
+
+func isNumericText(t *Table, row int, col int) bool {
+	_, numeric := toFloat64(t.GetValue(row, col))
+	return numeric
+}
+
+// ansiBackground returns escape sequence to set background to c, with black or white foreground readable on it.
+func ansiBackground(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	r, g, b = r>>8, g>>8, b>>8
+	foreground := 97
+	if 299*r+587*g+114*b > 128000 {
+		foreground = 30
+	}
+	return fmt.Sprintf("\x1b[48;2;%d;%d;%dm\x1b[%dm", r, g, b, foreground)
+}
+
+var cellTextReplacer = strings.NewReplacer("\r\n", "↵", "\n", "↵", "\t", " ")
+
+// formatCellText replaces line breaks and tabs which break the grid.
+func formatCellText(s string) string {
+	return cellTextReplacer.Replace(s)
+}
+
+// truncateText shortens s to maxWidth columns ending with an ellipsis.
+func truncateText(s string, maxWidth int) string {
+	if maxWidth <= 0 || textWidth(s) <= maxWidth {
+		return s
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		if width+runeWidth(r) > maxWidth-1 {
+			break
+		}
+		b.WriteRune(r)
+		width += runeWidth(r)
+	}
+	return b.String() + "…"
+}
+
+// textWidth returns the number of columns s occupies in terminals.
+func textWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// runeWidth returns 2 for East Asian wide characters, otherwise 1.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x1100 || r == utf8.RuneError:
+		return 1
+	case r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,71 @@
+package herschel
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	table := NewTable(4, 2)
+	table.PutValuesAtRow(0, "name", "price")
+	table.PutValuesAtRow(1, "apple pie", 1234.5)
+	table.PutValuesAtRow(2, "寿司", 3)
+	table.PutValuesAtRow(3, "c", nil)
+	table.SetNumberFormatPattern(1, 1, "#,##0.00")
+
+	t.Run("Box", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		if err := table.Format(buf, FormatOptions{MaxCellWidth: 8, MaxRows: 3}); err != nil {
+			t.Fatal(err)
+		}
+		want := `┌───┬──────────┬──────────┐
+│   │ A        │ B        │
+├───┼──────────┼──────────┤
+│ 1 │ name     │ price    │
+│ 2 │ apple p… │ 1,234.50 │
+│ 3 │ 寿司     │        3 │
+└───┴──────────┴──────────┘
+... 1 more rows
+`
+		if buf.String() != want {
+			t.Errorf("Unexpected text:\n%s", buf.String())
+		}
+	})
+
+	t.Run("Tab", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		if err := table.Format(buf, FormatOptions{Style: TextStyleTab, MaxRows: 2}); err != nil {
+			t.Fatal(err)
+		}
+		want := `   A          B
+1  name       price
+2  apple pie  1,234.50
+... 2 more rows
+`
+		if buf.String() != want {
+			t.Errorf("Unexpected text:\n%s", buf.String())
+		}
+	})
+
+	t.Run("Color", func(t *testing.T) {
+		table.SetBackgroundColor(0, 0, color.Black)
+		buf := bytes.NewBuffer(nil)
+		if err := table.Format(buf, FormatOptions{Color: true}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), "\x1b[48;2;0;0;0m\x1b[97m name      \x1b[0m") {
+			t.Errorf("Background color should be rendered:\n%q", buf.String())
+		}
+	})
+}
+
+func TestTruncateText(t *testing.T) {
+	if got := truncateText("寿司ランチ", 5); got != "寿司…" {
+		t.Errorf("Unexpected text: %s", got)
+	}
+	if got := truncateText("abc", 3); got != "abc" {
+		t.Errorf("Unexpected text: %s", got)
+	}
+}
@@ -47,3 +47,19 @@ func (t *Table) GetInt64Value(row int, col int) int64 {
 	}
 	return 0
 }
+
+// GetDisplayString returns value of cell formatted as spreadsheet displays it, applying its number format pattern.
+// Cells with number format type but without pattern are formatted with the default pattern of the type.
+// Cells without number format are returned as the strings a user would enter. Formulas are evaluated by Evaluate.
+func (t *Table) GetDisplayString(row int, col int) string {
+	v := t.Evaluate(row, col)
+	pattern := t.getNumberFormatPattern(row, col)
+	if len(pattern) == 0 {
+		formatType := t.getNumberFormatType(row, col)
+		if len(formatType) == 0 {
+			return userEnteredString(v)
+		}
+		pattern = defaultNumberFormatPatterns[formatType]
+	}
+	return formatValue(v, pattern)
+}
@@ -94,3 +94,40 @@ func TestTable_GetInt64Value(t *testing.T) {
 		})
 	}
 }
+
+func TestTable_GetDisplayString(t *testing.T) {
+	table := NewTable(1, 6)
+	table.PutValue(0, 0, 1234.5)
+	table.SetNumberFormatPattern(0, 0, "#,##0.00")
+	table.PutValue(0, 1, 0.125)
+	table.SetNumberFormatType(0, 1, "PERCENT")
+	table.PutValue(0, 2, 45291.5)
+	table.SetNumberFormatType(0, 2, "DATE_TIME")
+	table.PutValue(0, 3, 1234.5)
+	table.PutValue(0, 4, "Hello")
+	table.SetNumberFormatPattern(0, 4, "\"<\"@\">\"")
+
+	type args struct {
+		row int
+		col int
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"Pattern", args{0, 0}, "1,234.50"},
+		{"DefaultPatternOfType", args{0, 1}, "12.50%"},
+		{"DateTime", args{0, 2}, "12/31/2023 12:00:00"},
+		{"WithoutFormat", args{0, 3}, "1234.5"},
+		{"Text", args{0, 4}, "<Hello>"},
+		{"EmptyCell", args{0, 5}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := table.GetDisplayString(tt.args.row, tt.args.col); got != tt.want {
+				t.Errorf("Table.GetDisplayString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package herschel
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// HTMLOptions configures ToHTML.
+type HTMLOptions struct {
+	// Class is set to class attribute of the table element.
+	Class string
+	// MergeHeaderCells merges adjacent cells of header rows with the same value, and empty cells following a value, into a cell with colspan.
+	MergeHeaderCells bool
+}
+
+// ToHTML writes table as HTML table element. Frozen rows are written in thead.
+// Values are formatted with number format patterns, and background colors are written as inline styles.
+func (t *Table) ToHTML(w io.Writer, opts HTMLOptions) error {
+	bw := bufio.NewWriter(w)
+
+	if len(opts.Class) > 0 {
+		fmt.Fprintf(bw, "<table class=\"%s\">\n", html.EscapeString(opts.Class))
+	} else {
+		bw.WriteString("<table>\n")
+	}
+
+	headerRows := int(t.FrozenRowCount)
+	if headerRows > t.rows {
+		headerRows = t.rows
+	}
+	if headerRows > 0 {
+		bw.WriteString("<thead>\n")
+		for row := 0; row < headerRows; row++ {
+			t.writeHTMLRow(bw, row, "th", opts.MergeHeaderCells)
+		}
+		bw.WriteString("</thead>\n")
+	}
+	if headerRows < t.rows {
+		bw.WriteString("<tbody>\n")
+		for row := headerRows; row < t.rows; row++ {
+			t.writeHTMLRow(bw, row, "td", false)
+		}
+		bw.WriteString("</tbody>\n")
+	}
+
+	bw.WriteString("</table>\n")
+	return bw.Flush()
+}
+
+func (t *Table) writeHTMLRow(w *bufio.Writer, row int, tag string, merge bool) {
+	w.WriteString("<tr>")
+	for col := 0; col < t.cols; col++ {
+		span := 1
+		if merge {
+			for col+span < t.cols && t.mergesWithLeftCell(row, col, col+span) {
+				span++
+			}
+		}
+
+		styles := []string{}
+		if c := t.getBackgroundColor(row, col); c != color.Transparent {
+			styles = append(styles, "background-color:"+cssColor(c))
+		}
+		if _, numeric := toFloat64(t.GetValue(row, col)); numeric {
+			styles = append(styles, "text-align:right")
+		}
+
+		w.WriteString("<" + tag)
+		if span > 1 {
+			fmt.Fprintf(w, " colspan=\"%d\"", span)
+		}
+		if len(styles) > 0 {
+			fmt.Fprintf(w, " style=\"%s\"", strings.Join(styles, ";"))
+		}
+		w.WriteString(">" + html.EscapeString(t.GetDisplayString(row, col)) + "</" + tag + ">")
+		col += span - 1
+	}
+	w.WriteString("</tr>\n")
+}
+
+// mergesWithLeftCell reports whether cell at col is merged into the cell at start on its left.
+func (t *Table) mergesWithLeftCell(row int, start int, col int) bool {
+	if t.GetValue(row, start) == nil || !sameBackgroundColor(t.getBackgroundColor(row, col), t.getBackgroundColor(row, start)) {
+		return false
+	}
+	v := t.GetValue(row, col)
+	return v == nil || userEnteredString(v) == userEnteredString(t.GetValue(row, start))
+}
+
+// cssColor returns hex notation of color such as #ff0000.
+func cssColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// ToMarkdown writes table as GitHub flavored markdown table. The first row is the header.
+// Columns of numbers are aligned right, and columns of booleans are centered.
+func (t *Table) ToMarkdown(w io.Writer) error {
+	if t.rows == 0 || t.cols == 0 {
+		return nil
+	}
+	bw := bufio.NewWriter(w)
+
+	for row := 0; row < t.rows; row++ {
+		cells := make([]string, t.cols)
+		for col := range cells {
+			cells[col] = markdownEscape(t.GetDisplayString(row, col))
+		}
+		bw.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+
+		if row == 0 {
+			aligns := make([]string, t.cols)
+			for col := range aligns {
+				aligns[col] = t.markdownAlignment(col)
+			}
+			bw.WriteString("|" + strings.Join(aligns, "|") + "|\n")
+		}
+	}
+	return bw.Flush()
+}
+
+// markdownAlignment returns delimiter of column aligned by types of values below the header.
+func (t *Table) markdownAlignment(col int) string {
+	numbers, bools, others := 0, 0, 0
+	for row := 1; row < t.rows; row++ {
+		v := t.GetValue(row, col)
+		if _, numeric := toFloat64(v); numeric {
+			numbers++
+		} else if _, ok := v.(bool); ok {
+			bools++
+		} else if v != nil {
+			others++
+		}
+	}
+	switch {
+	case others == 0 && bools == 0 && numbers > 0:
+		return " ---: "
+	case others == 0 && numbers == 0 && bools > 0:
+		return " :---: "
+	}
+	return " --- "
+}
+
+var markdownReplacer = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+func markdownEscape(s string) string {
+	return markdownReplacer.Replace(s)
+}
@@ -0,0 +1,60 @@
+package herschel
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+func TestHTMLExport(t *testing.T) {
+	table := NewTable(4, 3)
+	table.PutValuesAtRow(0, "Sales", nil, "Note")
+	table.PutValuesAtRow(1, "2021", "2022", "")
+	table.PutValuesAtRow(2, 1234.5, 0.25, "<b>")
+	table.SetNumberFormatPattern(2, 0, "#,##0.00")
+	table.SetNumberFormatPattern(2, 1, "0%")
+	table.SetBackgroundColor(2, 2, color.RGBA{255, 0, 0, 255})
+	table.FrozenRowCount = 2
+
+	buf := bytes.NewBuffer(nil)
+	if err := table.ToHTML(buf, HTMLOptions{Class: "report", MergeHeaderCells: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<table class="report">
+<thead>
+<tr><th colspan="2">Sales</th><th>Note</th></tr>
+<tr><th>2021</th><th>2022</th><th></th></tr>
+</thead>
+<tbody>
+<tr><td style="text-align:right">1,234.50</td><td style="text-align:right">25%</td><td style="background-color:#ff0000">&lt;b&gt;</td></tr>
+<tr><td></td><td></td><td></td></tr>
+</tbody>
+</table>
+`
+	if buf.String() != want {
+		t.Errorf("Unexpected html:\n%s", buf.String())
+	}
+}
+
+func TestMarkdownExport(t *testing.T) {
+	table := NewTable(3, 3)
+	table.PutValuesAtRow(0, "name", "count", "active")
+	table.PutValuesAtRow(1, "a|b", 1, true)
+	table.PutValuesAtRow(2, "c", 1234.5, nil)
+	table.SetNumberFormatPattern(2, 1, "#,##0")
+
+	buf := bytes.NewBuffer(nil)
+	if err := table.ToMarkdown(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `| name | count | active |
+| --- | ---: | :---: |
+| a\|b | 1 | TRUE |
+| c | 1,235 |  |
+`
+	if buf.String() != want {
+		t.Errorf("Unexpected markdown:\n%s", buf.String())
+	}
+}
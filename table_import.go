@@ -0,0 +1,151 @@
+package herschel
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HeaderMode specifies whether the first row of csv is a header.
+type HeaderMode int
+
+const (
+	// HeaderAuto treats the first row as a header when its cells are distinct text and another row has a number, bool or date below them.
+	// It works with or without CSVOptions.InferTypes.
+	HeaderAuto HeaderMode = iota
+	// HeaderPresent treats the first row as a header.
+	HeaderPresent
+	// HeaderAbsent treats all rows as data.
+	HeaderAbsent
+)
+
+// DefaultDateLayouts are layouts used to infer dates when CSVOptions.DateLayouts is empty.
+var DefaultDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"2006/01/02",
+	"2006/01/02 15:04:05",
+}
+
+// CSVOptions configures FromCSV.
+type CSVOptions struct {
+	// Delimiter separates fields. Comma is used when zero.
+	Delimiter rune
+	// Comment starts a comment line when not zero.
+	Comment rune
+	// Header specifies whether the first row is a header.
+	// Header cells are kept as strings and the header row is frozen.
+	Header HeaderMode
+	// InferTypes converts fields to int, float64, bool and time.Time when possible. Otherwise all values are strings.
+	InferTypes bool
+	// DateLayouts are layouts tried to parse dates. DefaultDateLayouts is used when empty.
+	DateLayouts []string
+}
+
+// FromCSV returns a table read from csv. Rows shorter than the widest row are padded with empty cells.
+func FromCSV(r io.Reader, opts CSVOptions) (*Table, error) {
+	cr := csv.NewReader(r)
+	if opts.Delimiter != 0 {
+		cr.Comma = opts.Delimiter
+	}
+	cr.Comment = opts.Comment
+	cr.FieldsPerRecord = -1
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read csv")
+	}
+
+	layouts := opts.DateLayouts
+	if len(layouts) == 0 {
+		layouts = DefaultDateLayouts
+	}
+
+	values := make([][]interface{}, len(records))
+	for i, record := range records {
+		values[i] = make([]interface{}, len(record))
+		for j, field := range record {
+			if opts.InferTypes {
+				values[i][j] = inferValue(field, layouts)
+			} else if len(field) > 0 {
+				values[i][j] = field
+			}
+		}
+	}
+
+	hasHeader := opts.Header == HeaderPresent || (opts.Header == HeaderAuto && looksLikeHeader(records, layouts))
+	if hasHeader && len(records) > 0 {
+		for j, field := range records[0] {
+			values[0][j] = field
+		}
+	}
+
+	t := tableFromValues(values)
+	if hasHeader && len(records) > 0 {
+		t.FrozenRowCount = 1
+	}
+	return t, nil
+}
+
+// inferValue converts field to int, float64, bool or time.Time. Empty field becomes nil.
+func inferValue(field string, dateLayouts []string) interface{} {
+	if len(field) == 0 {
+		return nil
+	}
+	if i, err := strconv.Atoi(field); err == nil && !hasLeadingZero(field) {
+		return i
+	}
+	if f, err := strconv.ParseFloat(field, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) && !hasLeadingZero(field) {
+		return f
+	}
+	if b, err := strconv.ParseBool(strings.ToLower(field)); err == nil && len(field) > 1 {
+		return b
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, field); err == nil {
+			return t
+		}
+	}
+	return field
+}
+
+// hasLeadingZero reports whether numeric field has zeros which would be lost by conversion, such as zip codes.
+func hasLeadingZero(field string) bool {
+	field = strings.TrimLeft(field, "+-")
+	return len(field) > 1 && field[0] == '0' && field[1] != '.'
+}
+
+// looksLikeHeader reports whether the first row has distinct text cells and another row has a value which is not text in a column with header text.
+// Fields are inferred regardless of CSVOptions.InferTypes, so a header is detected in csv read as strings. Empty header cells are ignored.
+func looksLikeHeader(records [][]string, dateLayouts []string) bool {
+	if len(records) < 2 {
+		return false
+	}
+	seen := map[string]bool{}
+	for _, field := range records[0] {
+		if len(field) == 0 {
+			continue
+		}
+		if _, ok := inferValue(field, dateLayouts).(string); !ok || seen[field] {
+			return false
+		}
+		seen[field] = true
+	}
+	for _, record := range records[1:] {
+		for j, field := range record {
+			if j >= len(records[0]) || len(records[0][j]) == 0 || len(field) == 0 {
+				continue
+			}
+			if _, ok := inferValue(field, dateLayouts).(string); !ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package herschel
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSVImport(t *testing.T) {
+	csv := `# exported at 2022-07-01
+name;count;ratio;active;since;zip
+apple;3;0.5;true;2022-01-02;0123
+banana;-1;1e3;FALSE;2022/03/04 10:20:30
+cherry
+`
+	table, err := FromCSV(strings.NewReader(csv), CSVOptions{Delimiter: ';', Comment: '#', InferTypes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if table.GetRows() != 4 || table.GetCols() != 6 {
+		t.Fatalf("Unexpected table size %d x %d", table.GetRows(), table.GetCols())
+	}
+	if table.FrozenRowCount != 1 {
+		t.Errorf("Header row should be frozen, got: %d", table.FrozenRowCount)
+	}
+
+	expected := [][]interface{}{
+		{"name", "count", "ratio", "active", "since", "zip"},
+		{"apple", 3, 0.5, true, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), "0123"},
+		{"banana", -1, 1000.0, false, time.Date(2022, 3, 4, 10, 20, 30, 0, time.UTC), nil},
+		{"cherry", nil, nil, nil, nil, nil},
+	}
+	if !reflect.DeepEqual(table.Values(), expected) {
+		t.Errorf("Unexpected values: %v", table.Values())
+	}
+}
+
+func TestCSVImportHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		csv    string
+		opts   CSVOptions
+		frozen int64
+		first  interface{}
+	}{
+		{"AutoWithHeader", "id\n1\n", CSVOptions{InferTypes: true}, 1, "id"},
+		{"AutoWithoutHeader", "a\nb\n", CSVOptions{InferTypes: true}, 0, "a"},
+		{"AutoNumericFirstRow", "1\n2\n", CSVOptions{InferTypes: true}, 0, 1},
+		{"Present", "1\n2\n", CSVOptions{InferTypes: true, Header: HeaderPresent}, 1, "1"},
+		{"Absent", "id\n1\n", CSVOptions{InferTypes: true, Header: HeaderAbsent}, 0, "id"},
+		{"NoInference", "id\n1\n", CSVOptions{}, 1, "id"},
+		{"NoInferenceWithoutHeader", "a\nb\n", CSVOptions{}, 0, "a"},
+		{"EmptyHeaderCell", "id,,name\n1,2,x\n", CSVOptions{InferTypes: true}, 1, "id"},
+		{"EmptyHeaderCellWithoutInference", "id,,name\n1,2,x\n", CSVOptions{}, 1, "id"},
+		{"DuplicateHeaderCells", "a,a\n1,2\n", CSVOptions{InferTypes: true}, 0, "a"},
+		{"NumericDataBelowEmptyHeader", "a,\nb,1\n", CSVOptions{InferTypes: true}, 0, "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := FromCSV(strings.NewReader(tt.csv), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if table.FrozenRowCount != tt.frozen {
+				t.Errorf("FrozenRowCount should be %d, got: %d", tt.frozen, table.FrozenRowCount)
+			}
+			if v := table.GetValue(0, 0); v != tt.first {
+				t.Errorf("First cell should be %v, got: %v", tt.first, v)
+			}
+		})
+	}
+}
+
+func TestUserEnteredValues(t *testing.T) {
+	date := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+	values := [][]interface{}{{"a"}, {date, date.Add(90 * time.Minute)}}
+
+	converted := userEnteredValues(values)
+	if !reflect.DeepEqual(converted, [][]interface{}{{"a"}, {"2022-01-02", "2022-01-02 01:30:00"}}) {
+		t.Errorf("Unexpected values: %v", converted)
+	}
+	if values[1][0] != date {
+		t.Error("Original values should not be modified.")
+	}
+}
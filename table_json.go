@@ -0,0 +1,337 @@
+package herschel
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// JSONKeyOrder specifies order of keys of objects.
+type JSONKeyOrder int
+
+const (
+	// JSONKeyOrderColumns orders keys as columns of header row. FromJSON adds columns in order keys first appear.
+	JSONKeyOrderColumns JSONKeyOrder = iota
+	// JSONKeyOrderSorted orders keys alphabetically.
+	JSONKeyOrderSorted
+)
+
+// JSONOptions configures conversion between table and JSON.
+// The header row of table holds keys, and each following row is an object.
+type JSONOptions struct {
+	KeyOrder JSONKeyOrder
+	// Flatten maps nested objects to headers joined by Separator such as a.b.c.
+	// Without Flatten, nested objects and arrays are kept as JSON text in a cell.
+	Flatten bool
+	// Separator joins keys of nested objects. Dot is used when empty.
+	Separator string
+	// Typed decodes integral numbers as int and RFC 3339 strings as time.Time.
+	// Otherwise numbers are float64 as values read from spreadsheet, and strings are kept as is.
+	Typed bool
+	// Indent indents JSON written by ToJSON.
+	Indent string
+}
+
+func (opts JSONOptions) separator() string {
+	if len(opts.Separator) == 0 {
+		return "."
+	}
+	return opts.Separator
+}
+
+// ToJSON writes rows of table as an array of objects keyed by the header row.
+func (t *Table) ToJSON(w io.Writer, opts JSONOptions) error {
+	objects, err := t.jsonObjects(opts)
+	if err != nil {
+		return err
+	}
+	if objects == nil {
+		objects = []*jsonObject{}
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", opts.Indent)
+	return e.Encode(objects)
+}
+
+// ToJSONLines writes each row of table as an object keyed by the header row in a line.
+func (t *Table) ToJSONLines(w io.Writer, opts JSONOptions) error {
+	objects, err := t.jsonObjects(opts)
+	if err != nil {
+		return err
+	}
+	e := json.NewEncoder(w)
+	for _, o := range objects {
+		if err := e.Encode(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FromJSON returns a table read from an array of objects. Keys become the header row.
+func FromJSON(r io.Reader, opts JSONOptions) (*Table, error) {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	if tok, err := d.Token(); err != nil {
+		return nil, errors.Wrap(err, "failed to decode json")
+	} else if tok != json.Delim('[') {
+		return nil, errors.Errorf("array of objects expected, got: %v", tok)
+	}
+
+	objects := []*jsonObject{}
+	for d.More() {
+		o, err := decodeJSONObject(d)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode json of object %d", len(objects)+1)
+		}
+		objects = append(objects, o)
+	}
+	if _, err := d.Token(); err != nil {
+		return nil, errors.Wrap(err, "failed to decode json")
+	}
+	return tableFromJSONObjects(objects, opts), nil
+}
+
+// FromJSONLines returns a table read from objects in lines. Keys become the header row.
+func FromJSONLines(r io.Reader, opts JSONOptions) (*Table, error) {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	objects := []*jsonObject{}
+	for d.More() {
+		o, err := decodeJSONObject(d)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode json of object %d", len(objects)+1)
+		}
+		objects = append(objects, o)
+	}
+	return tableFromJSONObjects(objects, opts), nil
+}
+
+// jsonObject is a JSON object which keeps order of keys.
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{values: map[string]interface{}{}}
+}
+
+func (o *jsonObject) set(key string, value interface{}) {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode value of %s", key)
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func (t *Table) jsonObjects(opts JSONOptions) ([]*jsonObject, error) {
+	if t.rows <= 1 || t.cols == 0 {
+		return nil, nil
+	}
+
+	// Columns without headers and with duplicated headers are skipped.
+	cols := []int{}
+	seen := map[string]bool{}
+	for col := 0; col < t.cols; col++ {
+		header := t.GetStringValue(0, col)
+		if len(header) > 0 && !seen[header] {
+			seen[header] = true
+			cols = append(cols, col)
+		}
+	}
+	if opts.KeyOrder == JSONKeyOrderSorted {
+		sort.SliceStable(cols, func(i, j int) bool {
+			return t.GetStringValue(0, cols[i]) < t.GetStringValue(0, cols[j])
+		})
+	}
+
+	objects := []*jsonObject{}
+	for row := 1; row < t.rows; row++ {
+		o := newJSONObject()
+		for _, col := range cols {
+			header := t.GetStringValue(0, col)
+			if !opts.Flatten {
+				o.set(header, t.GetValue(row, col))
+				continue
+			}
+			if err := o.setPath(strings.Split(header, opts.separator()), t.GetValue(row, col)); err != nil {
+				return nil, errors.Wrapf(err, "header %s", header)
+			}
+		}
+		objects = append(objects, o)
+	}
+	return objects, nil
+}
+
+// setPath sets value to nested object at path.
+func (o *jsonObject) setPath(path []string, value interface{}) error {
+	for _, key := range path[:len(path)-1] {
+		child, exists := o.values[key]
+		if !exists {
+			child = newJSONObject()
+			o.set(key, child)
+		}
+		nested, ok := child.(*jsonObject)
+		if !ok {
+			return errors.Errorf("%s has both value and nested keys", key)
+		}
+		o = nested
+	}
+	key := path[len(path)-1]
+	if _, isObject := o.values[key].(*jsonObject); isObject {
+		return errors.Errorf("%s has both value and nested keys", key)
+	}
+	o.set(key, value)
+	return nil
+}
+
+func tableFromJSONObjects(objects []*jsonObject, opts JSONOptions) *Table {
+	headers := []string{}
+	cols := map[string]int{}
+	rows := make([]*jsonObject, len(objects))
+	for i, o := range objects {
+		rows[i] = newJSONObject()
+		flattenJSONObject(rows[i], "", o, opts)
+		for _, key := range rows[i].keys {
+			if _, exists := cols[key]; !exists {
+				cols[key] = len(headers)
+				headers = append(headers, key)
+			}
+		}
+	}
+	if opts.KeyOrder == JSONKeyOrderSorted {
+		sort.Strings(headers)
+		for i, h := range headers {
+			cols[h] = i
+		}
+	}
+
+	t := NewTable(len(objects)+1, len(headers))
+	for col, h := range headers {
+		t.PutValue(0, col, h)
+	}
+	for i, row := range rows {
+		for _, key := range row.keys {
+			t.PutValue(i+1, cols[key], row.values[key])
+		}
+	}
+	t.FrozenRowCount = 1
+	return t
+}
+
+func flattenJSONObject(dst *jsonObject, prefix string, o *jsonObject, opts JSONOptions) {
+	for _, key := range o.keys {
+		v := o.values[key]
+		if len(prefix) > 0 {
+			key = prefix + opts.separator() + key
+		}
+		if nested, ok := v.(*jsonObject); ok && opts.Flatten {
+			flattenJSONObject(dst, key, nested, opts)
+			continue
+		}
+		dst.set(key, jsonCellValue(v, opts))
+	}
+}
+
+// decodeJSONObject decodes an object keeping order of keys. Nested objects are decoded as *jsonObject.
+func decodeJSONObject(d *json.Decoder) (*jsonObject, error) {
+	v, err := decodeJSONValue(d)
+	if err != nil {
+		return nil, err
+	}
+	o, ok := v.(*jsonObject)
+	if !ok {
+		return nil, errors.Errorf("object expected, got: %v", v)
+	}
+	return o, nil
+}
+
+func decodeJSONValue(d *json.Decoder) (interface{}, error) {
+	tok, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		o := newJSONObject()
+		for d.More() {
+			key, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeJSONValue(d)
+			if err != nil {
+				return nil, err
+			}
+			o.set(key.(string), v)
+		}
+		_, err := d.Token()
+		return o, err
+	case json.Delim('['):
+		a := []interface{}{}
+		for d.More() {
+			v, err := decodeJSONValue(d)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		_, err := d.Token()
+		return a, err
+	}
+	return tok, nil
+}
+
+// jsonCellValue converts decoded JSON value to value of cell.
+func jsonCellValue(v interface{}, opts JSONOptions) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		if opts.Typed {
+			if i, err := value.Int64(); err == nil && int64(int(i)) == i {
+				return int(i)
+			}
+		}
+		f, _ := value.Float64()
+		return f
+	case string:
+		if opts.Typed {
+			if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				return t
+			}
+		}
+		return value
+	case *jsonObject, []interface{}:
+		b, _ := json.Marshal(value)
+		return string(b)
+	}
+	return v
+}
@@ -0,0 +1,110 @@
+package herschel
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONExport(t *testing.T) {
+	table := NewTable(3, 3)
+	table.PutValuesAtRow(0, "name", "user.age", "user.active")
+	table.PutValuesAtRow(1, "a", 20, true)
+	table.PutValuesAtRow(2, "b", nil, false)
+
+	tests := []struct {
+		name  string
+		lines bool
+		opts  JSONOptions
+		want  string
+	}{
+		{"Columns", false, JSONOptions{}, `[{"name":"a","user.age":20,"user.active":true},{"name":"b","user.age":null,"user.active":false}]` + "\n"},
+		{"Sorted", true, JSONOptions{KeyOrder: JSONKeyOrderSorted}, `{"name":"a","user.active":true,"user.age":20}` + "\n" + `{"name":"b","user.active":false,"user.age":null}` + "\n"},
+		{"Flatten", true, JSONOptions{Flatten: true}, `{"name":"a","user":{"age":20,"active":true}}` + "\n" + `{"name":"b","user":{"age":null,"active":false}}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			var err error
+			if tt.lines {
+				err = table.ToJSONLines(buf, tt.opts)
+			} else {
+				err = table.ToJSON(buf, tt.opts)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Unexpected json: %s", buf.String())
+			}
+		})
+	}
+
+	conflict := NewTable(2, 2)
+	conflict.PutValuesAtRow(0, "a", "a.b")
+	if err := conflict.ToJSON(bytes.NewBuffer(nil), JSONOptions{Flatten: true}); err == nil {
+		t.Error("Header with both value and nested keys should fail.")
+	}
+}
+
+func TestJSONImport(t *testing.T) {
+	input := `[
+		{"name": "a", "user": {"age": 20, "since": "2022-01-02T03:04:05Z"}, "tags": ["x"]},
+		{"name": "b", "score": 1.5}
+	]`
+
+	table, err := FromJSON(strings.NewReader(input), JSONOptions{Flatten: true, Typed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{
+		{"name", "user.age", "user.since", "tags", "score"},
+		{"a", 20, time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC), `["x"]`, nil},
+		{"b", nil, nil, nil, 1.5},
+	}
+	if !reflect.DeepEqual(table.Values(), expected) {
+		t.Errorf("Unexpected values: %v", table.Values())
+	}
+	if table.FrozenRowCount != 1 {
+		t.Errorf("Header row should be frozen.")
+	}
+
+	table, err = FromJSON(strings.NewReader(input), JSONOptions{KeyOrder: JSONKeyOrderSorted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = [][]interface{}{
+		{"name", "score", "tags", "user"},
+		{"a", nil, `["x"]`, `{"age":20,"since":"2022-01-02T03:04:05Z"}`},
+		{"b", 1.5, nil, nil},
+	}
+	if !reflect.DeepEqual(table.Values(), expected) {
+		t.Errorf("Unexpected values: %v", table.Values())
+	}
+
+	if _, err := FromJSON(strings.NewReader(`{"name": "a"}`), JSONOptions{}); err == nil {
+		t.Error("Object should fail.")
+	}
+}
+
+func TestJSONLinesImport(t *testing.T) {
+	input := "{\"event\": \"open\", \"count\": 1}\n\n{\"event\": \"close\", \"at\": \"now\"}\n"
+	table, err := FromJSONLines(strings.NewReader(input), JSONOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{
+		{"event", "count", "at"},
+		{"open", 1.0, nil},
+		{"close", nil, "now"},
+	}
+	if !reflect.DeepEqual(table.Values(), expected) {
+		t.Errorf("Unexpected values: %v", table.Values())
+	}
+
+	if _, err := FromJSONLines(strings.NewReader("{\"a\": 1}\n[1]\n"), JSONOptions{}); err == nil {
+		t.Error("Line without object should fail.")
+	}
+}
@@ -11,15 +11,16 @@ func (t *Table) AppendTableAtBottom(a *Table) *Table {
 		maxCols = a.cols
 	}
 	newTable := NewTable(t.rows+a.rows, maxCols)
-	newTable.FrozenRowCount = t.FrozenRowCount
-	newTable.FrozenColumnCount = t.FrozenColumnCount
-	newTable.copyFromTable(t)
+	newTable.copyPropertiesFromTable(t)
+	newTable.copyColumnWidthsFromTable(t.cols, a, t.cols, a.cols-t.cols)
 
+	for row := 0; row < t.rows; row++ {
+		newTable.copyRowFromTable(row, 0, t, row, 0, t.cols)
+	}
 	for row := 0; row < a.rows; row++ {
-		for col := 0; col < a.cols; col++ {
-			newTable.copyCellFromTable(row+t.rows, col, a, row, col)
-		}
+		newTable.copyRowFromTable(row+t.rows, 0, a, row, 0, a.cols)
 	}
+	newTable.shiftFormulas(t.rows, t.rows+a.rows, 0, a.cols, offsetMap(t.rows), nil)
 	return newTable
 }
 
@@ -30,15 +31,16 @@ func (t *Table) AppendTableAtRight(a *Table) *Table {
 		maxRows = a.rows
 	}
 	newTable := NewTable(maxRows, t.cols+a.cols)
-	newTable.FrozenRowCount = t.FrozenRowCount
-	newTable.FrozenColumnCount = t.FrozenColumnCount
-	newTable.copyFromTable(t)
+	newTable.copyPropertiesFromTable(t)
+	newTable.copyColumnWidthsFromTable(t.cols, a, 0, a.cols)
 
+	for row := 0; row < t.rows; row++ {
+		newTable.copyRowFromTable(row, 0, t, row, 0, t.cols)
+	}
 	for row := 0; row < a.rows; row++ {
-		for col := 0; col < a.cols; col++ {
-			newTable.copyCellFromTable(row, col+t.cols, a, row, col)
-		}
+		newTable.copyRowFromTable(row, t.cols, a, row, 0, a.cols)
 	}
+	newTable.shiftFormulas(0, a.rows, t.cols, t.cols+a.cols, nil, offsetMap(t.cols))
 	return newTable
 }
 
@@ -53,32 +55,91 @@ func (t *Table) SubTable(rowStart, colStart, numRows, numCols int) (*Table, erro
 	}
 
 	s := NewTable(numRows, numCols)
+	s.copyColumnWidthsFromTable(0, t, colStart, numCols)
 	for row := 0; row < numRows; row++ {
-		for col := 0; col < numCols; col++ {
-			s.copyCellFromTable(row, col, t, row+rowStart, col+colStart)
-		}
+		s.copyRowFromTable(row, 0, t, row+rowStart, colStart, numCols)
 	}
+	s.shiftFormulas(0, numRows, 0, numCols, offsetMap(-rowStart), offsetMap(-colStart))
 	return s, nil
 }
 
 // SubTableByFilteringRows returns new instance of table with filtered rows from original table.
 func (t *Table) SubTableByFilteringRows(f func(values []interface{}) bool) *Table {
-	s := NewTable(0, t.cols)
-
+	matched := []int{}
 	for i := 0; i < t.rows; i++ {
-		r := t.GetValuesAtRow(i)
-		if f(r) {
-			st, err := t.SubTable(i, 0, 1, t.cols)
-			if err != nil {
-				panic(err)
-			}
-			s = s.AppendTableAtBottom(st)
+		if f(t.GetValuesAtRow(i)) {
+			matched = append(matched, i)
 		}
 	}
 
+	s := NewTable(len(matched), t.cols)
+	s.growable = t.growable
+	s.copyColumnWidthsFromTable(0, t, 0, t.cols)
+	newRows := make(map[int]int, len(matched))
+	for row, i := range matched {
+		s.copyRowFromTable(row, 0, t, i, 0, t.cols)
+		newRows[i] = row
+	}
+	// Rows below the table move up by the number of filtered rows.
+	s.shiftFormulas(0, s.rows, 0, s.cols, func(i int) (int, bool) {
+		if i >= t.rows {
+			return i - t.rows + len(matched), true
+		}
+		row, ok := newRows[i]
+		return row, ok
+	}, nil)
 	return s
 }
 
+// UpsertResult represents rows written by Upsert.
+type UpsertResult struct {
+	// Rows are indices of rows where each given row was written.
+	Rows []int
+	// Updated is the number of given rows which updated existing rows.
+	Updated int
+	// Inserted is the number of given rows appended at bottom.
+	Inserted int
+}
+
+// Upsert updates rows whose values of key columns match the given rows, and appends the others at bottom.
+// Values are matched as the strings a user would enter, so 1 and "1" are equal. Table grows to fit the given rows.
+func (t *Table) Upsert(keyCols []int, rows ...[]interface{}) UpsertResult {
+	return t.UpsertBelowHeader(0, keyCols, rows...)
+}
+
+// UpsertBelowHeader is like Upsert, but the first headerRows rows are never matched nor updated.
+func (t *Table) UpsertBelowHeader(headerRows int, keyCols []int, rows ...[]interface{}) UpsertResult {
+	result := UpsertResult{}
+	index := t.rowIndexByKey(keyCols)
+	for key, matched := range index {
+		for len(matched) > 0 && matched[0] < headerRows {
+			matched = matched[1:]
+		}
+		index[key] = matched
+	}
+
+	for _, values := range rows {
+		key := rowKey(values, keyCols)
+		row := -1
+		if matched := index[key]; len(matched) > 0 {
+			row = matched[0]
+			result.Updated++
+		} else {
+			row = t.rows
+			if row < headerRows {
+				row = headerRows
+			}
+			index[key] = []int{row}
+			result.Inserted++
+		}
+
+		t.expand(row+1, len(values))
+		t.PutValuesAtRow(row, values...)
+		result.Rows = append(result.Rows, row)
+	}
+	return result
+}
+
 // InsertColAtIndex inserts new column at index
 func (t *Table) InsertColAtIndex(index int) error {
 	if index < 0 || index > t.cols {
@@ -87,15 +148,17 @@ func (t *Table) InsertColAtIndex(index int) error {
 
 	t.cols++
 
-	for col := t.cols - 1; col > index; col-- {
-		for row := 0; row < t.rows; row++ {
-			t.copyCellFromTable(row, col, t, row, col-1)
-		}
-	}
-
 	for row := 0; row < t.rows; row++ {
-		t.clearCell(row, index)
+		if r := t.cells[row]; index < len(r) {
+			r = append(r, nil)
+			copy(r[index+1:], r[index:])
+			r[index] = nil
+			t.cells[row] = r
+		}
+		t.shiftStyles(row, index, 1)
 	}
+	t.shiftColumnWidths(index, 1)
+	t.shiftFormulas(0, t.rows, 0, t.cols, nil, insertMap(index, 1))
 
 	return nil
 }
@@ -106,33 +169,104 @@ func (t *Table) RemoveColAtIndex(index int) error {
 		return fmt.Errorf("invalid index %d", index)
 	}
 
-	for col := index; col < t.cols-1; col++ {
-		for row := 0; row < t.rows; row++ {
-			t.copyCellFromTable(row, col, t, row, col+1)
+	for row := 0; row < t.rows; row++ {
+		if r := t.cells[row]; index < len(r) {
+			copy(r[index:], r[index+1:])
+			r[len(r)-1] = nil
+			t.cells[row] = r[:len(r)-1]
 		}
+		delete(t.styles[row], index)
+		t.shiftStyles(row, index+1, -1)
 	}
+	delete(t.columnWidths, index)
+	t.shiftColumnWidths(index+1, -1)
+	t.shiftFormulas(0, t.rows, 0, t.cols, nil, removeMap(index))
 
 	t.cols = t.cols - 1
 
 	return nil
 }
 
+// shiftStyles moves formats of cells at row from col to the right by delta.
+func (t *Table) shiftStyles(row int, from int, delta int) {
+	styles := t.styles[row]
+	if len(styles) == 0 {
+		return
+	}
+	shifted := make(map[int]cellStyle, len(styles))
+	for col, style := range styles {
+		if col >= from {
+			col += delta
+		}
+		shifted[col] = style
+	}
+	t.styles[row] = shifted
+}
+
+// shiftColumnWidths moves widths of columns from col to the right by delta.
+func (t *Table) shiftColumnWidths(from int, delta int) {
+	if len(t.columnWidths) == 0 {
+		return
+	}
+	shifted := make(map[int]int, len(t.columnWidths))
+	for col, width := range t.columnWidths {
+		if col >= from {
+			col += delta
+		}
+		shifted[col] = width
+	}
+	t.columnWidths = shifted
+}
+
+// copyColumnWidthsFromTable copies widths of numCols columns from source table.
+func (t *Table) copyColumnWidthsFromTable(targetCol int, sourceTable *Table, sourceCol int, numCols int) {
+	for col, width := range sourceTable.columnWidths {
+		if col >= sourceCol && col < sourceCol+numCols {
+			t.SetColumnWidth(targetCol+col-sourceCol, width)
+		}
+	}
+}
+
 func (t *Table) copyCellFromTable(targetRow int, targetCol int, sourceTable *Table, sourceRow int, sourceCol int) {
 	t.PutValue(targetRow, targetCol, sourceTable.GetValue(sourceRow, sourceCol))
-	t.SetBackgroundColor(targetRow, targetCol, sourceTable.getBackgroundColor(sourceRow, sourceCol))
-	t.SetNumberFormatPattern(targetRow, targetCol, sourceTable.getNumberFormatPattern(sourceRow, sourceCol))
-	t.SetNumberFormatType(targetRow, targetCol, sourceTable.getNumberFormatType(sourceRow, sourceCol))
+	t.setStyle(targetRow, targetCol, sourceTable.getStyle(sourceRow, sourceCol))
 }
 
-func (t *Table) copyFromTable(a *Table) {
-	for row := 0; row < a.rows; row++ {
-		for col := 0; col < a.cols; col++ {
-			t.copyCellFromTable(row, col, a, row, col)
+// copyRowFromTable copies numCols cells of a row from source table. Target row must have no values and formats.
+func (t *Table) copyRowFromTable(targetRow int, targetCol int, sourceTable *Table, sourceRow int, sourceCol int, numCols int) {
+	if sourceRow >= len(sourceTable.cells) {
+		return
+	}
+
+	src := sourceTable.cells[sourceRow]
+	if sourceCol < len(src) {
+		end := sourceCol + numCols
+		if end > len(src) {
+			end = len(src)
+		}
+		if end > sourceCol {
+			copy(t.rowForWrite(targetRow, targetCol+end-sourceCol-1)[targetCol:], src[sourceCol:end])
+		}
+	}
+
+	for col, style := range sourceTable.styles[sourceRow] {
+		if col >= sourceCol && col < sourceCol+numCols {
+			t.setStyle(targetRow, targetCol+col-sourceCol, style)
 		}
 	}
 }
 
-//ClearValues clears all values of table.
+func (t *Table) copyPropertiesFromTable(a *Table) {
+	t.growable = a.growable
+	t.copyColumnWidthsFromTable(0, a, 0, a.cols)
+	t.FrozenRowCount = a.FrozenRowCount
+	t.FrozenColumnCount = a.FrozenColumnCount
+	t.ProtectedRowCount = a.ProtectedRowCount
+	t.HeaderProtection = a.HeaderProtection
+	t.RemoveHeaderProtection = a.RemoveHeaderProtection
+}
+
+// ClearValues clears all values of table.
 func (t *Table) ClearValues() error {
 
 	for row := 0; row < t.rows; row++ {
@@ -144,7 +278,7 @@ func (t *Table) ClearValues() error {
 	return nil
 }
 
-//ClearValuesInRange clears the value of a cell in the specified range.
+// ClearValuesInRange clears the value of a cell in the specified range.
 func (t *Table) ClearValuesInRange(rowStart, colStart, numRows, numCols int) error {
 	// Validate range
 	if (rowStart + numRows) > t.rows {
@@ -1,6 +1,10 @@
 package herschel
 
-import "testing"
+import (
+	"image/color"
+	"reflect"
+	"testing"
+)
 
 func TestSubTable(t *testing.T) {
 	orig := NewTable(3, 3)
@@ -89,6 +93,51 @@ func TestRemovingColumn(t *testing.T) {
 	}
 }
 
+func TestRemovingLastColumn(t *testing.T) {
+	orig := NewTable(2, 3)
+	orig.PutValuesAtRow(0, "a", "b", "c")
+	orig.PutValuesAtRow(1, "d", "e", "f")
+	orig.SetBackgroundColor(0, 2, color.White)
+
+	if err := orig.RemoveColAtIndex(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := orig.InsertColAtIndex(2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removed values and formats should not reappear in the inserted column.
+	if v := orig.GetValue(0, 2); v != nil {
+		t.Errorf("Inserted column should be empty, got: %v", v)
+	}
+	if c := orig.getBackgroundColor(0, 2); c != color.Transparent {
+		t.Errorf("Inserted column should have no background color, got: %v", c)
+	}
+	if c := orig.getBackgroundColor(0, 1); c != color.White {
+		t.Errorf("Background color should be moved to column 1, got: %v", c)
+	}
+}
+
+func TestInsertColShiftsFormats(t *testing.T) {
+	orig := NewTable(1, 2)
+	orig.SetBackgroundColor(0, 1, color.White)
+	orig.SetNumberFormatPattern(0, 1, "0.00")
+
+	if err := orig.InsertColAtIndex(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if c := orig.getBackgroundColor(0, 1); c != color.Transparent {
+		t.Errorf("Column 1 should have no background color, got: %v", c)
+	}
+	if c := orig.getBackgroundColor(0, 2); c != color.White {
+		t.Errorf("Background color should be moved to column 2, got: %v", c)
+	}
+	if p := orig.getNumberFormatPattern(0, 2); p != "0.00" {
+		t.Errorf("Number format should be moved to column 2, got: %s", p)
+	}
+}
+
 func TestClearValues(t *testing.T) {
 	orig := NewTable(3, 3)
 	orig.PutValuesAtRow(0, "a", "b", "c")
@@ -228,3 +277,65 @@ func TestInsertCol(t *testing.T) {
 		}
 	})
 }
+
+func TestUpsert(t *testing.T) {
+	table := NewTable(3, 3)
+	table.PutValuesAtRow(0, "id", "name", "city")
+	table.PutValuesAtRow(1, "1", "John", "London")
+	table.PutValuesAtRow(2, "2", "Peter", "New York")
+
+	result := table.Upsert([]int{0},
+		[]interface{}{2, "Peter", "Tokyo"},
+		[]interface{}{"3", "Mary", "Paris", "extra"},
+		[]interface{}{"3", "Mary", "Berlin"},
+	)
+
+	if result.Updated != 2 || result.Inserted != 1 {
+		t.Errorf("2 updated and 1 inserted expected, got: %+v", result)
+	}
+	if !reflect.DeepEqual(result.Rows, []int{2, 3, 3}) {
+		t.Errorf("Rows = %v, want [2 3 3]", result.Rows)
+	}
+	if table.GetRows() != 4 || table.GetCols() != 4 {
+		t.Fatalf("Table should grow to 4 x 4, got: %d x %d", table.GetRows(), table.GetCols())
+	}
+	if table.GetValue(2, 2) != "Tokyo" {
+		t.Errorf("Value at 2, 2 should be Tokyo, got: %v", table.GetValue(2, 2))
+	}
+	if table.GetValue(3, 2) != "Berlin" || table.GetValue(3, 3) != "extra" {
+		t.Errorf("Unexpected values at row 3: %v", table.GetValuesAtRow(3))
+	}
+	if table.GetValue(1, 2) != "London" {
+		t.Errorf("Row 1 should not be changed, got: %v", table.GetValuesAtRow(1))
+	}
+
+	t.Run("MultipleKeyCols", func(t *testing.T) {
+		table := NewTable(2, 3)
+		table.PutValuesAtRow(0, "a", "1", "foo")
+		table.PutValuesAtRow(1, "a", "2", "bar")
+
+		result := table.Upsert([]int{0, 1}, []interface{}{"a", "2", "baz"})
+		if result.Updated != 1 || table.GetValue(1, 2) != "baz" {
+			t.Errorf("Row 1 should be updated, got: %+v %v", result, table.GetValuesAtRow(1))
+		}
+	})
+
+	t.Run("BelowHeader", func(t *testing.T) {
+		table := NewTable(2, 2)
+		table.PutValuesAtRow(0, "id", "name")
+		table.PutValuesAtRow(1, "a", "foo")
+
+		result := table.UpsertBelowHeader(1, []int{0}, []interface{}{"id", "X"}, []interface{}{"a", "bar"})
+		if result.Updated != 1 || result.Inserted != 1 || !reflect.DeepEqual(result.Rows, []int{2, 1}) {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+		if !reflect.DeepEqual(table.GetValuesAtRow(0), []interface{}{"id", "name"}) {
+			t.Errorf("Header row should not be changed, got: %v", table.GetValuesAtRow(0))
+		}
+
+		empty := NewTable(0, 2)
+		if result := empty.UpsertBelowHeader(1, []int{0}, []interface{}{"a"}); !reflect.DeepEqual(result.Rows, []int{1}) {
+			t.Errorf("Row should be inserted below header, got: %+v", result)
+		}
+	})
+}
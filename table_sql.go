@@ -0,0 +1,225 @@
+package herschel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultSQLBatchRows is the number of rows inserted by a statement when SQLInsertOptions.BatchRows is zero.
+const DefaultSQLBatchRows = 100
+
+// SQLInsertOptions configures InsertInto.
+type SQLInsertOptions struct {
+	// Columns maps headers of table to column names of database table. Only mapped headers are inserted.
+	// All headers are inserted as column names when nil.
+	Columns map[string]string
+	// BatchRows is the number of rows inserted by a statement.
+	BatchRows int
+	// Placeholder returns the placeholder of n-th parameter counted from 1. "?" is used when nil.
+	// Use DollarPlaceholder for PostgreSQL.
+	Placeholder func(n int) string
+	// QuoteIdentifier quotes table and column names. Names are quoted by double quotes when nil.
+	QuoteIdentifier func(name string) string
+}
+
+// DollarPlaceholder returns placeholders of PostgreSQL such as $1.
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// TableFromRows returns a table of query results. Column names become the header row, which is frozen.
+// NULL becomes an empty cell, []byte becomes string, and integers become int. Values of driver.Valuer such as sql.NullString are converted to their values.
+func TableFromRows(rows *sql.Rows) (*Table, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get columns")
+	}
+
+	values := [][]interface{}{}
+	for rows.Next() {
+		scanned := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range scanned {
+			dest[i] = &scanned[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan row %d", len(values)+1)
+		}
+		for i, v := range scanned {
+			value, err := sqlCellValue(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to convert %s of row %d", columns[i], len(values)+1)
+			}
+			scanned[i] = value
+		}
+		values = append(values, scanned)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read rows")
+	}
+
+	t := NewTable(len(values)+1, len(columns))
+	for col, name := range columns {
+		t.PutValue(0, col, name)
+	}
+	for row, rowValues := range values {
+		t.PutValuesAtRow(row+1, rowValues...)
+	}
+	t.FrozenRowCount = 1
+	return t, nil
+}
+
+// sqlCellValue converts a value scanned from database to value of cell.
+func sqlCellValue(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return string(value), nil
+	case int64:
+		return int(value), nil
+	case driver.Valuer:
+		converted, err := value.Value()
+		if err != nil {
+			return nil, err
+		}
+		return sqlCellValue(converted)
+	}
+	return v, nil
+}
+
+// InsertInto inserts rows of table following the header row into database table in a transaction.
+// Rows without values are skipped, and empty cells are inserted as NULL.
+func (t *Table) InsertInto(ctx context.Context, db *sql.DB, tableName string, opts SQLInsertOptions) error {
+	cols, names, err := t.sqlInsertColumns(opts.Columns)
+	if err != nil {
+		return err
+	}
+	if len(cols) == 0 {
+		return errors.New("no columns to insert")
+	}
+
+	rows := []int{}
+	for row := 1; row < t.rows; row++ {
+		for _, col := range cols {
+			if !isEmptyValue(t.GetValue(row, col)) {
+				rows = append(rows, row)
+				break
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	quote := opts.QuoteIdentifier
+	if quote == nil {
+		quote = quoteSQLIdentifier
+	}
+	placeholder := opts.Placeholder
+	if placeholder == nil {
+		placeholder = func(int) string { return "?" }
+	}
+	batchRows := opts.BatchRows
+	if batchRows <= 0 {
+		batchRows = DefaultSQLBatchRows
+	}
+
+	quotedNames := make([]string, len(names))
+	for i, name := range names {
+		quotedNames[i] = quote(name)
+	}
+	prefix := "INSERT INTO " + quote(tableName) + " (" + strings.Join(quotedNames, ", ") + ") VALUES "
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	for start := 0; start < len(rows); start += batchRows {
+		end := start + batchRows
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		var query strings.Builder
+		query.WriteString(prefix)
+		args := make([]interface{}, 0, (end-start)*len(cols))
+		for i, row := range rows[start:end] {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteString("(")
+			for j, col := range cols {
+				if j > 0 {
+					query.WriteString(", ")
+				}
+				args = append(args, sqlArgument(t.GetValue(row, col)))
+				query.WriteString(placeholder(len(args)))
+			}
+			query.WriteString(")")
+		}
+
+		if _, err := tx.ExecContext(ctx, query.String(), args...); err != nil {
+			tx.Rollback()
+			return errors.Wrapf(err, "failed to insert rows %d-%d", rows[start]+1, rows[end-1]+1)
+		}
+	}
+	return errors.Wrap(tx.Commit(), "failed to commit transaction")
+}
+
+// sqlInsertColumns returns indices of columns to insert and their column names in database.
+func (t *Table) sqlInsertColumns(mapping map[string]string) ([]int, []string, error) {
+	headers := map[string]int{}
+	cols := []int{}
+	names := []string{}
+	for col := 0; col < t.cols; col++ {
+		header := userEnteredString(t.GetValue(0, col))
+		if len(header) == 0 {
+			continue
+		}
+		if _, exists := headers[header]; exists {
+			return nil, nil, errors.Errorf("duplicated header %s", header)
+		}
+		headers[header] = col
+
+		if mapping == nil {
+			cols = append(cols, col)
+			names = append(names, header)
+		} else if name, ok := mapping[header]; ok {
+			cols = append(cols, col)
+			names = append(names, name)
+		}
+	}
+	for header := range mapping {
+		if _, exists := headers[header]; !exists {
+			return nil, nil, errors.Wrapf(ErrHeaderNotFound, "header %s", header)
+		}
+	}
+	return cols, names, nil
+}
+
+// sqlArgument converts value of cell to argument of query. Empty cells are NULL.
+func sqlArgument(v interface{}) interface{} {
+	if isEmptyValue(v) {
+		return nil
+	}
+	return v
+}
+
+func isEmptyValue(v interface{}) bool {
+	return v == nil || v == ""
+}
+
+// quoteSQLIdentifier quotes name by double quotes. Each part of qualified name such as schema.table is quoted.
+func quoteSQLIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
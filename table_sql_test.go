@@ -0,0 +1,277 @@
+package herschel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// stubDriver is an in-memory database/sql driver returning stubRows for queries and recording executed statements.
+type stubDriver struct {
+	mu         sync.Mutex
+	columns    []string
+	rows       [][]driver.Value
+	execs      []stubExec
+	failExec   bool
+	committed  bool
+	rolledBack bool
+}
+
+type stubExec struct {
+	query string
+	args  []driver.Value
+}
+
+var stubDriverCount struct {
+	sync.Mutex
+	n int
+}
+
+// openStubDB registers a new stub driver and opens a database with it.
+func openStubDB(t *testing.T, d *stubDriver) *sql.DB {
+	stubDriverCount.Lock()
+	stubDriverCount.n++
+	name := "herschel-stub-" + strconv.Itoa(stubDriverCount.n)
+	stubDriverCount.Unlock()
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func (d *stubDriver) Open(name string) (driver.Conn, error) {
+	return &stubConn{d: d}, nil
+}
+
+type stubConn struct {
+	d *stubDriver
+}
+
+func (c *stubConn) Prepare(query string) (driver.Stmt, error) {
+	return &stubStmt{d: c.d, query: query}, nil
+}
+
+func (c *stubConn) Close() error {
+	return nil
+}
+
+func (c *stubConn) Begin() (driver.Tx, error) {
+	return &stubTx{d: c.d}, nil
+}
+
+type stubTx struct {
+	d *stubDriver
+}
+
+func (tx *stubTx) Commit() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.committed = true
+	return nil
+}
+
+func (tx *stubTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.rolledBack = true
+	return nil
+}
+
+type stubStmt struct {
+	d     *stubDriver
+	query string
+}
+
+func (s *stubStmt) Close() error {
+	return nil
+}
+
+func (s *stubStmt) NumInput() int {
+	return -1
+}
+
+func (s *stubStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if s.d.failExec {
+		return nil, errors.New("exec failed")
+	}
+	s.d.execs = append(s.d.execs, stubExec{query: s.query, args: args})
+	return driver.RowsAffected(0), nil
+}
+
+func (s *stubStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &stubRows{columns: s.d.columns, rows: s.d.rows}, nil
+}
+
+type stubRows struct {
+	columns []string
+	rows    [][]driver.Value
+	index   int
+}
+
+func (r *stubRows) Columns() []string {
+	return r.columns
+}
+
+func (r *stubRows) Close() error {
+	return nil
+}
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.index >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.index])
+	r.index++
+	return nil
+}
+
+func TestTableFromRows(t *testing.T) {
+	createdAt := time.Date(2024, 4, 1, 9, 30, 0, 0, time.UTC)
+	db := openStubDB(t, &stubDriver{
+		columns: []string{"id", "name", "score", "active", "created_at", "note"},
+		rows: [][]driver.Value{
+			{int64(1), []byte("apple"), 1.5, true, createdAt, sql.NullString{String: "fresh", Valid: true}},
+			{int64(2), "banana", nil, false, nil, sql.NullString{}},
+			{int64(3), "cherry", sql.NullFloat64{Float64: 2.5, Valid: true}, sql.NullBool{Bool: true, Valid: true}, sql.NullTime{Time: createdAt, Valid: true}, sql.NullInt64{Int64: 7, Valid: true}},
+		},
+	})
+
+	rows, err := db.QueryContext(context.Background(), "SELECT * FROM fruits")
+	if err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+	defer rows.Close()
+
+	table, err := TableFromRows(rows)
+	if err != nil {
+		t.Fatalf("Failed to read rows: %v", err)
+	}
+	if table.GetRows() != 4 || table.GetCols() != 6 {
+		t.Fatalf("Unexpected size: %d x %d", table.GetRows(), table.GetCols())
+	}
+	if table.FrozenRowCount != 1 {
+		t.Errorf("Unexpected frozen row count: %d", table.FrozenRowCount)
+	}
+
+	want := [][]interface{}{
+		{"id", "name", "score", "active", "created_at", "note"},
+		{1, "apple", 1.5, true, createdAt, "fresh"},
+		{2, "banana", nil, false, nil, nil},
+		{3, "cherry", 2.5, true, createdAt, 7},
+	}
+	for row, values := range want {
+		for col, v := range values {
+			if got := table.GetValue(row, col); !reflect.DeepEqual(got, v) {
+				t.Errorf("Unexpected value at (%d, %d): %#v, want %#v", row, col, got, v)
+			}
+		}
+	}
+}
+
+func TestTableFromRowsWithoutRows(t *testing.T) {
+	db := openStubDB(t, &stubDriver{columns: []string{"id", "name"}})
+	rows, err := db.Query("SELECT * FROM fruits")
+	if err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+	defer rows.Close()
+
+	table, err := TableFromRows(rows)
+	if err != nil {
+		t.Fatalf("Failed to read rows: %v", err)
+	}
+	if table.GetRows() != 1 || table.GetStringValue(0, 1) != "name" {
+		t.Errorf("Unexpected table: %d rows, header %v", table.GetRows(), table.GetValuesAtRow(0))
+	}
+}
+
+func fruitsTable() *Table {
+	table := NewTable(5, 4)
+	table.PutValuesAtRow(0, "id", "name", "price", "memo")
+	table.PutValuesAtRow(1, 1, "apple", 1.5, "")
+	table.PutValuesAtRow(2, 2, "banana", 0.25, "ripe")
+	// Row 3 is empty
+	table.PutValuesAtRow(4, 3, "cherry", 4, nil)
+	return table
+}
+
+func TestInsertInto(t *testing.T) {
+	d := &stubDriver{}
+	db := openStubDB(t, d)
+
+	if err := fruitsTable().InsertInto(context.Background(), db, "public.fruits", SQLInsertOptions{BatchRows: 2, Placeholder: DollarPlaceholder}); err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+
+	if len(d.execs) != 2 {
+		t.Fatalf("Unexpected number of statements: %d", len(d.execs))
+	}
+	wantQuery := `INSERT INTO "public"."fruits" ("id", "name", "price", "memo") VALUES ($1, $2, $3, $4), ($5, $6, $7, $8)`
+	if d.execs[0].query != wantQuery {
+		t.Errorf("Unexpected query: %s", d.execs[0].query)
+	}
+	wantArgs := []driver.Value{int64(1), "apple", 1.5, nil, int64(2), "banana", 0.25, "ripe"}
+	if !reflect.DeepEqual(d.execs[0].args, wantArgs) {
+		t.Errorf("Unexpected args: %#v", d.execs[0].args)
+	}
+	if !strings.HasSuffix(d.execs[1].query, "VALUES ($1, $2, $3, $4)") {
+		t.Errorf("Unexpected query: %s", d.execs[1].query)
+	}
+	if !reflect.DeepEqual(d.execs[1].args, []driver.Value{int64(3), "cherry", int64(4), nil}) {
+		t.Errorf("Unexpected args: %#v", d.execs[1].args)
+	}
+	if !d.committed {
+		t.Errorf("Transaction is not committed")
+	}
+}
+
+func TestInsertIntoWithColumnMapping(t *testing.T) {
+	d := &stubDriver{}
+	db := openStubDB(t, d)
+
+	opts := SQLInsertOptions{
+		Columns:         map[string]string{"name": "fruit_name", "id": "fruit_id"},
+		QuoteIdentifier: func(name string) string { return "`" + name + "`" },
+	}
+	if err := fruitsTable().InsertInto(context.Background(), db, "fruits", opts); err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+
+	if len(d.execs) != 1 {
+		t.Fatalf("Unexpected number of statements: %d", len(d.execs))
+	}
+	if want := "INSERT INTO `fruits` (`fruit_id`, `fruit_name`) VALUES (?, ?), (?, ?), (?, ?)"; d.execs[0].query != want {
+		t.Errorf("Unexpected query: %s", d.execs[0].query)
+	}
+
+	opts.Columns["origin"] = "origin"
+	if err := fruitsTable().InsertInto(context.Background(), db, "fruits", opts); err == nil || !strings.Contains(err.Error(), "origin") {
+		t.Errorf("Unexpected error for missing header: %v", err)
+	}
+}
+
+func TestInsertIntoRollsBack(t *testing.T) {
+	d := &stubDriver{failExec: true}
+	db := openStubDB(t, d)
+
+	if err := fruitsTable().InsertInto(context.Background(), db, "fruits", SQLInsertOptions{}); err == nil {
+		t.Fatalf("Error expected")
+	}
+	if !d.rolledBack || d.committed {
+		t.Errorf("Unexpected transaction state: rolled back %v, committed %v", d.rolledBack, d.committed)
+	}
+}
@@ -2,8 +2,11 @@ package herschel
 
 import (
 	"fmt"
+	"image/color"
 	"strings"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 func TestTable(t *testing.T) {
@@ -260,3 +263,64 @@ func TestTableToMapSliceConversion(t *testing.T) {
 		t.Errorf("row[1][last_name] = %v, want Brown", rows[1]["last_name"])
 	}
 }
+
+func TestSetValue(t *testing.T) {
+	table := NewTable(2, 2)
+
+	if err := table.SetValue(1, 1, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if table.GetValue(1, 1) != "a" {
+		t.Errorf("a expected, got: %v", table.GetValue(1, 1))
+	}
+
+	for _, pos := range [][2]int{{2, 0}, {0, 2}, {-1, 0}} {
+		if err := table.SetValue(pos[0], pos[1], "x"); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("ErrOutOfRange expected at %v, got: %v", pos, err)
+		}
+	}
+
+	if err := table.SetRow(0, "a", "b", "c"); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("ErrOutOfRange expected, got: %v", err)
+	}
+	if table.GetValue(0, 0) != nil {
+		t.Errorf("Row should not be changed on error, got: %v", table.GetValuesAtRow(0))
+	}
+	if err := table.SetRow(0, "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if table.GetValue(0, 1) != "b" {
+		t.Errorf("b expected, got: %v", table.GetValue(0, 1))
+	}
+}
+
+func TestGrowableTable(t *testing.T) {
+	table := NewGrowableTable(1, 1)
+
+	table.PutValue(2, 3, "a")
+	if table.GetRows() != 3 || table.GetCols() != 4 {
+		t.Errorf("Table should grow to 3 x 4, got: %d x %d", table.GetRows(), table.GetCols())
+	}
+
+	if err := table.SetRow(4, "a", "b", "c", "d", "e"); err != nil {
+		t.Fatal(err)
+	}
+	if table.GetRows() != 5 || table.GetCols() != 5 {
+		t.Errorf("Table should grow to 5 x 5, got: %d x %d", table.GetRows(), table.GetCols())
+	}
+
+	table.SetBackgroundColor(6, 0, color.Black)
+	table.SetNumberFormatPattern(7, 0, "#,##0")
+	if table.GetRows() != 8 {
+		t.Errorf("Table should grow to 8 rows, got: %d", table.GetRows())
+	}
+
+	if err := table.SetValue(-1, 0, "x"); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("ErrOutOfRange expected for negative row, got: %v", err)
+	}
+
+	table.SetGrowable(false)
+	if err := table.SetValue(8, 0, "x"); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("ErrOutOfRange expected after disabling growth, got: %v", err)
+	}
+}
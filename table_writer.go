@@ -1,8 +1,8 @@
 package herschel
 
 import (
-	"fmt"
 	"image/color"
+	"time"
 
 	sheets "google.golang.org/api/sheets/v4"
 )
@@ -13,13 +13,43 @@ func (client Client) setCellFormats(spreadsheetID string, sheetName string, tabl
 		return err
 	}
 	if !exists {
-		return fmt.Errorf("sheet not found with name: %s", sheetName)
+		return sheetNotFoundError(sheetName)
 	}
 	// Background color
-	return client.updateCellFormats(spreadsheetID, sheetID, table)
+	if err := client.updateCellFormats(spreadsheetID, sheetID, table); err != nil {
+		return err
+	}
+	return client.protectHeaderRows(spreadsheetID, sheetID, table)
+}
+
+// protectHeaderRows protects header rows of table, and removes header protections added by previous writes.
+// Nothing is requested when table has no protected rows, unless RemoveHeaderProtection is set.
+func (client Client) protectHeaderRows(spreadsheetID string, sheetID int64, table *Table) error {
+	if table.ProtectedRowCount <= 0 && !table.RemoveHeaderProtection {
+		return nil
+	}
+	existing, err := getProtectedRanges(client, spreadsheetID, sheetID)
+	if err != nil {
+		return err
+	}
+	requests, err := headerProtectionRequests(sheetID, existing, table)
+	if err != nil {
+		return err
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+	return client.batchUpdate(spreadsheetID, requests)
 }
 
 func (client Client) updateCellFormats(spreadsheetID string, sheetID int64, table *Table) error {
+	requests := frozenCountRequests(sheetID, table)
+	requests = append(requests, columnWidthRequests(sheetID, table)...)
+	requests = append(requests, rowFormatRequests(sheetID, table, 0, table.rows)...)
+	return client.batchUpdate(spreadsheetID, requests)
+}
+
+func frozenCountRequests(sheetID int64, table *Table) []*sheets.Request {
 	requests := []*sheets.Request{}
 
 	if table.FrozenRowCount > 0 {
@@ -51,12 +81,41 @@ func (client Client) updateCellFormats(spreadsheetID string, sheetID int64, tabl
 		}
 		requests = append(requests, &req)
 	}
+	return requests
+}
+
+func columnWidthRequests(sheetID int64, table *Table) []*sheets.Request {
+	requests := []*sheets.Request{}
+	for _, col := range table.widthCols() {
+		requests = append(requests, &sheets.Request{
+			UpdateDimensionProperties: &sheets.UpdateDimensionPropertiesRequest{
+				Range: &sheets.DimensionRange{
+					SheetId:         sheetID,
+					Dimension:       "COLUMNS",
+					StartIndex:      int64(col),
+					EndIndex:        int64(col) + 1,
+					ForceSendFields: []string{"SheetId", "StartIndex"},
+				},
+				Properties: &sheets.DimensionProperties{PixelSize: int64(table.GetColumnWidth(col))},
+				Fields:     "pixelSize",
+			},
+		})
+	}
+	return requests
+}
 
-	for col := 0; col < table.cols; col++ {
-		for row := 0; row < table.rows; row++ {
+// rowFormatRequests returns requests to set formats of cells in rows from rowStart (inclusive) to rowEnd (exclusive).
+func rowFormatRequests(sheetID int64, table *Table, rowStart int, rowEnd int) []*sheets.Request {
+	requests := []*sheets.Request{}
+
+	// Only cells with formats are visited.
+	for row := rowStart; row < rowEnd; row++ {
+		for _, col := range table.styledCols(row) {
+			if col >= table.cols {
+				continue
+			}
 			c := table.getBackgroundColor(row, col)
 			if c != color.Transparent {
-				r, g, b, a := c.RGBA()
 				req := sheets.Request{
 					RepeatCell: &sheets.RepeatCellRequest{
 						Range: &sheets.GridRange{SheetId: sheetID,
@@ -67,7 +126,7 @@ func (client Client) updateCellFormats(spreadsheetID string, sheetID int64, tabl
 						},
 						Cell: &sheets.CellData{
 							UserEnteredFormat: &sheets.CellFormat{
-								BackgroundColor: &sheets.Color{Alpha: float64(a) / 65536, Blue: float64(b) / 65536, Green: float64(g) / 65536, Red: float64(r) / 65536},
+								BackgroundColor: toSheetsColor(c),
 							},
 						},
 						Fields: "userEnteredFormat(backgroundColor)",
@@ -106,5 +165,35 @@ func (client Client) updateCellFormats(spreadsheetID string, sheetID int64, tabl
 		}
 	}
 
-	return client.batchUpdate(spreadsheetID, requests)
+	return requests
+}
+
+// userEnteredValues returns values with time.Time replaced by strings spreadsheet parses as dates.
+// values is returned as is when it has no time.Time.
+func userEnteredValues(values [][]interface{}) [][]interface{} {
+	var converted [][]interface{}
+	for i, row := range values {
+		var convertedRow []interface{}
+		for j, v := range row {
+			tv, ok := v.(time.Time)
+			if !ok {
+				continue
+			}
+			if convertedRow == nil {
+				convertedRow = append([]interface{}{}, row...)
+			}
+			convertedRow[j] = userEnteredTime(tv)
+		}
+		if convertedRow == nil {
+			continue
+		}
+		if converted == nil {
+			converted = append([][]interface{}{}, values...)
+		}
+		converted[i] = convertedRow
+	}
+	if converted == nil {
+		return values
+	}
+	return converted
 }
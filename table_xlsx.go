@@ -0,0 +1,714 @@
+package herschel
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	xlsxMainNamespace         = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xlsxRelationshipNamespace = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	xlsxPackageRelationships  = "http://schemas.openxmlformats.org/package/2006/relationships"
+	xlsxContentTypes          = "http://schemas.openxmlformats.org/package/2006/content-types"
+	xlsxXMLHeader             = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+	// Number format id of the first custom format. Smaller ids are built-in formats.
+	xlsxFirstCustomNumberFormatID = 164
+)
+
+// xlsxBuiltInNumberFormats are number formats which have ids without declaration in styles.
+var xlsxBuiltInNumberFormats = map[int]string{
+	1:  "0",
+	2:  "0.00",
+	3:  "#,##0",
+	4:  "#,##0.00",
+	9:  "0%",
+	10: "0.00%",
+	11: "0.00E+00",
+	12: "# ?/?",
+	13: "# ??/??",
+	14: "m/d/yyyy",
+	15: "d-mmm-yy",
+	16: "d-mmm",
+	17: "mmm-yy",
+	18: "h:mm AM/PM",
+	19: "h:mm:ss AM/PM",
+	20: "h:mm",
+	21: "h:mm:ss",
+	22: "m/d/yyyy h:mm",
+	37: "#,##0 ;(#,##0)",
+	38: "#,##0 ;[Red](#,##0)",
+	39: "#,##0.00;(#,##0.00)",
+	40: "#,##0.00;[Red](#,##0.00)",
+	45: "mm:ss",
+	46: "[h]:mm:ss",
+	47: "mm:ss.0",
+	48: "##0.0E+0",
+	49: "@",
+}
+
+// XLSXSheet is a sheet of xlsx workbook written by WriteXLSX.
+type XLSXSheet struct {
+	Name  string
+	Table *Table
+}
+
+// ToXLSX writes table as xlsx workbook with a sheet named Sheet1.
+func (t *Table) ToXLSX(w io.Writer) error {
+	return WriteXLSX(w, XLSXSheet{Name: "Sheet1", Table: t})
+}
+
+// WriteXLSX writes tables as sheets of xlsx workbook.
+// Values, number formats, background colors, frozen rows / cols and column widths are written.
+func WriteXLSX(w io.Writer, sheets ...XLSXSheet) error {
+	if len(sheets) == 0 {
+		return errors.New("workbook should have at least one sheet")
+	}
+	names := map[string]bool{}
+	for _, s := range sheets {
+		if len(s.Name) == 0 || s.Table == nil {
+			return errors.New("sheet should have name and table")
+		}
+		if utf8.RuneCountInString(s.Name) > 31 || strings.ContainsAny(s.Name, `[]:*?/\`) {
+			return errors.Errorf("invalid sheet name %s: it should have at most 31 characters and none of []:*?/\\", s.Name)
+		}
+		if names[strings.ToLower(s.Name)] {
+			return errors.Errorf("duplicated sheet name %s", s.Name)
+		}
+		names[strings.ToLower(s.Name)] = true
+	}
+
+	styles := newXLSXStyles()
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypesXML(len(sheets))},
+		{"_rels/.rels", xlsxRootRelationshipsXML()},
+		{"xl/workbook.xml", xlsxWorkbookXML(sheets)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRelationshipsXML(len(sheets))},
+	}
+	for i, s := range sheets {
+		files = append(files, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xlsxWorksheetXML(s.Table, styles)})
+	}
+	// Styles are written last, since they are collected while writing sheets.
+	files = append(files, struct {
+		name    string
+		content string
+	}{"xl/styles.xml", styles.xml()})
+
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s", f.name)
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return errors.Wrapf(err, "failed to write %s", f.name)
+		}
+	}
+	return zw.Close()
+}
+
+func xlsxContentTypesXML(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xlsxXMLHeader)
+	b.WriteString(`<Types xmlns="` + xlsxContentTypes + `">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func xlsxRootRelationshipsXML() string {
+	return xlsxXMLHeader +
+		`<Relationships xmlns="` + xlsxPackageRelationships + `">` +
+		`<Relationship Id="rId1" Type="` + xlsxRelationshipNamespace + `/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+}
+
+func xlsxWorkbookXML(sheets []XLSXSheet) string {
+	var b strings.Builder
+	b.WriteString(xlsxXMLHeader)
+	b.WriteString(`<workbook xmlns="` + xlsxMainNamespace + `" xmlns:r="` + xlsxRelationshipNamespace + `"><sheets>`)
+	for i, s := range sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(s.Name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func xlsxWorkbookRelationshipsXML(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xlsxXMLHeader)
+	b.WriteString(`<Relationships xmlns="` + xlsxPackageRelationships + `">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="%s/worksheet" Target="worksheets/sheet%d.xml"/>`, i, xlsxRelationshipNamespace, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="%s/styles" Target="styles.xml"/>`, sheets+1, xlsxRelationshipNamespace)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+func xlsxWorksheetXML(t *Table, styles *xlsxStyles) string {
+	var b strings.Builder
+	b.WriteString(xlsxXMLHeader)
+	b.WriteString(`<worksheet xmlns="` + xlsxMainNamespace + `" xmlns:r="` + xlsxRelationshipNamespace + `">`)
+
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0">`)
+	if t.FrozenRowCount > 0 || t.FrozenColumnCount > 0 {
+		b.WriteString(xlsxFrozenPaneXML(int(t.FrozenRowCount), int(t.FrozenColumnCount)))
+	}
+	b.WriteString(`</sheetView></sheetViews>`)
+
+	if cols := t.widthCols(); len(cols) > 0 {
+		b.WriteString(`<cols>`)
+		for _, col := range cols {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%s" customWidth="1"/>`, col+1, col+1, strconv.FormatFloat(xlsxColumnWidth(t.GetColumnWidth(col)), 'f', 2, 64))
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData>`)
+	for row := 0; row < t.rows; row++ {
+		fmt.Fprintf(&b, `<row r="%d">`, row+1)
+		for col := 0; col < t.cols; col++ {
+			b.WriteString(xlsxCellXML(t, row, col, styles))
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+func xlsxFrozenPaneXML(rows int, cols int) string {
+	pane := "bottomRight"
+	switch {
+	case cols == 0:
+		pane = "bottomLeft"
+	case rows == 0:
+		pane = "topRight"
+	}
+	attrs := ""
+	if cols > 0 {
+		attrs += fmt.Sprintf(` xSplit="%d"`, cols)
+	}
+	if rows > 0 {
+		attrs += fmt.Sprintf(` ySplit="%d"`, rows)
+	}
+	return fmt.Sprintf(`<pane%s topLeftCell="%s%d" activePane="%s" state="frozen"/>`, attrs, columnName(cols), rows+1, pane)
+}
+
+func xlsxCellXML(t *Table, row int, col int, styles *xlsxStyles) string {
+	v := t.GetValue(row, col)
+	style := t.getStyle(row, col)
+
+	valueType, value := "", ""
+	switch tv := v.(type) {
+	case nil:
+	case string:
+		valueType, value = "inlineStr", tv
+	case Formula:
+		// Result is computed by the application opening the workbook.
+		valueType, value = "str", strings.TrimPrefix(string(tv), "=")
+	case bool:
+		valueType, value = "b", "0"
+		if tv {
+			value = "1"
+		}
+	case time.Time:
+		value = strconv.FormatFloat(excelSerial(tv), 'f', -1, 64)
+		if len(style.numberFormat) == 0 {
+			style.numberFormat = "yyyy-mm-dd hh:mm:ss"
+			if userEnteredTime(tv) == tv.Format("2006-01-02") {
+				style.numberFormat = "yyyy-mm-dd"
+			}
+		}
+	default:
+		if f, ok := toFloat64(v); ok {
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				valueType, value = "e", "#NUM!"
+			} else if _, isFloat := v.(float64); isFloat {
+				value = strconv.FormatFloat(f, 'g', -1, 64)
+			} else {
+				value = userEnteredString(v)
+			}
+		} else {
+			valueType, value = "inlineStr", userEnteredString(v)
+		}
+	}
+
+	styleID := styles.id(style)
+	if v == nil && styleID == 0 {
+		return ""
+	}
+
+	attrs := fmt.Sprintf(` r="%s%d"`, columnName(col), row+1)
+	if styleID > 0 {
+		attrs += fmt.Sprintf(` s="%d"`, styleID)
+	}
+	if len(valueType) > 0 {
+		attrs += fmt.Sprintf(` t="%s"`, valueType)
+	}
+	switch {
+	case v == nil:
+		return `<c` + attrs + `/>`
+	case valueType == "inlineStr":
+		return `<c` + attrs + `><is><t xml:space="preserve">` + xmlEscape(value) + `</t></is></c>`
+	case valueType == "str":
+		return `<c` + attrs + `><f>` + xmlEscape(value) + `</f></c>`
+	}
+	return `<c` + attrs + `><v>` + value + `</v></c>`
+}
+
+// xlsxColumnWidth converts pixels to width in characters of the default font.
+func xlsxColumnWidth(pixels int) float64 {
+	return math.Max(float64(pixels-5)/7, 0)
+}
+
+// xlsxColumnPixels converts width in characters of the default font to pixels.
+func xlsxColumnPixels(width float64) int {
+	return int(math.Round(width*7 + 5))
+}
+
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// excelSerial returns serial number of date and time, the number of days since 1899-12-30 in wall clock of t.
+func excelSerial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	seconds := float64(wall.Unix()-excelEpoch.Unix()) + float64(wall.Nanosecond())/1e9
+	return seconds / 86400
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// xlsxStyles collects cell formats used in workbook.
+type xlsxStyles struct {
+	xfs           []xlsxXf
+	xfIDs         map[xlsxXf]int
+	numberFormats []string
+	numFmtIDs     map[string]int
+	fills         []string
+	fillIDs       map[string]int
+}
+
+type xlsxXf struct {
+	numFmtID int
+	fillID   int
+}
+
+func newXLSXStyles() *xlsxStyles {
+	s := &xlsxStyles{
+		xfs:       []xlsxXf{{}},
+		xfIDs:     map[xlsxXf]int{{}: 0},
+		numFmtIDs: map[string]int{},
+		// The first two fills are reserved.
+		fills:   []string{"", ""},
+		fillIDs: map[string]int{},
+	}
+	for id, code := range xlsxBuiltInNumberFormats {
+		if id <= 10 || id == 49 {
+			s.numFmtIDs[code] = id
+		}
+	}
+	return s
+}
+
+// id returns index of cell format with style, adding a new one if needed.
+func (s *xlsxStyles) id(style cellStyle) int {
+	xf := xlsxXf{}
+	if len(style.numberFormat) > 0 {
+		id, ok := s.numFmtIDs[style.numberFormat]
+		if !ok {
+			id = xlsxFirstCustomNumberFormatID + len(s.numberFormats)
+			s.numberFormats = append(s.numberFormats, style.numberFormat)
+			s.numFmtIDs[style.numberFormat] = id
+		}
+		xf.numFmtID = id
+	}
+	if c := style.backgroundColor; c != nil && c != color.Transparent {
+		argb := toARGB(c)
+		id, ok := s.fillIDs[argb]
+		if !ok {
+			id = len(s.fills)
+			s.fills = append(s.fills, argb)
+			s.fillIDs[argb] = id
+		}
+		xf.fillID = id
+	}
+
+	id, ok := s.xfIDs[xf]
+	if !ok {
+		id = len(s.xfs)
+		s.xfs = append(s.xfs, xf)
+		s.xfIDs[xf] = id
+	}
+	return id
+}
+
+func (s *xlsxStyles) xml() string {
+	var b strings.Builder
+	b.WriteString(xlsxXMLHeader)
+	b.WriteString(`<styleSheet xmlns="` + xlsxMainNamespace + `">`)
+	if len(s.numberFormats) > 0 {
+		fmt.Fprintf(&b, `<numFmts count="%d">`, len(s.numberFormats))
+		for i, code := range s.numberFormats {
+			fmt.Fprintf(&b, `<numFmt numFmtId="%d" formatCode="%s"/>`, xlsxFirstCustomNumberFormatID+i, xmlEscape(code))
+		}
+		b.WriteString(`</numFmts>`)
+	}
+	b.WriteString(`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>`)
+	fmt.Fprintf(&b, `<fills count="%d"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>`, len(s.fills))
+	for _, argb := range s.fills[2:] {
+		fmt.Fprintf(&b, `<fill><patternFill patternType="solid"><fgColor rgb="%s"/><bgColor indexed="64"/></patternFill></fill>`, argb)
+	}
+	b.WriteString(`</fills>`)
+	b.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+	fmt.Fprintf(&b, `<cellXfs count="%d">`, len(s.xfs))
+	for _, xf := range s.xfs {
+		fmt.Fprintf(&b, `<xf numFmtId="%d" fontId="0" fillId="%d" borderId="0" xfId="0"`, xf.numFmtID, xf.fillID)
+		if xf.numFmtID > 0 {
+			b.WriteString(` applyNumberFormat="1"`)
+		}
+		if xf.fillID > 0 {
+			b.WriteString(` applyFill="1"`)
+		}
+		b.WriteString(`/>`)
+	}
+	b.WriteString(`</cellXfs>`)
+	b.WriteString(`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>`)
+	b.WriteString(`</styleSheet>`)
+	return b.String()
+}
+
+// toARGB returns hex notation of opaque color such as FFFF0000.
+func toARGB(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("FF%02X%02X%02X", r>>8, g>>8, b>>8)
+}
+
+// fromARGB parses hex notation of color such as FFFF0000.
+func fromARGB(argb string) (color.Color, bool) {
+	if len(argb) == 6 {
+		argb = "FF" + argb
+	}
+	v, err := strconv.ParseUint(argb, 16, 32)
+	if len(argb) != 8 || err != nil {
+		return nil, false
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, true
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	s := t.Text
+	for _, r := range t.Runs {
+		s += r.Text
+	}
+	return s
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxStyleSheet struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	Fills []struct {
+		Pattern struct {
+			Type    string `xml:"patternType,attr"`
+			FgColor struct {
+				RGB string `xml:"rgb,attr"`
+			} `xml:"fgColor"`
+		} `xml:"patternFill"`
+	} `xml:"fills>fill"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+		FillID   int `xml:"fillId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxWorksheet struct {
+	Panes []struct {
+		XSplit float64 `xml:"xSplit,attr"`
+		YSplit float64 `xml:"ySplit,attr"`
+		State  string  `xml:"state,attr"`
+	} `xml:"sheetViews>sheetView>pane"`
+	Cols []struct {
+		Min         int     `xml:"min,attr"`
+		Max         int     `xml:"max,attr"`
+		Width       float64 `xml:"width,attr"`
+		CustomWidth bool    `xml:"customWidth,attr"`
+	} `xml:"cols>col"`
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string       `xml:"r,attr"`
+			S      int          `xml:"s,attr"`
+			T      string       `xml:"t,attr"`
+			V      *string      `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// xlsxReader reads parts of xlsx workbook.
+type xlsxReader struct {
+	files map[string]*zip.File
+}
+
+func newXLSXReader(r io.ReaderAt, size int64) (*xlsxReader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open xlsx")
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	return &xlsxReader{files: files}, nil
+}
+
+// decode decodes xml part with name into v. It reports false when the part does not exist.
+func (x *xlsxReader) decode(name string, v interface{}) (bool, error) {
+	f, ok := x.files[name]
+	if !ok {
+		return false, nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to open %s", name)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return false, errors.Wrapf(err, "failed to parse %s", name)
+	}
+	return true, nil
+}
+
+// sheetPaths returns names and paths of sheets in workbook order.
+func (x *xlsxReader) sheetPaths() ([]string, []string, error) {
+	workbook := xlsxWorkbook{}
+	ok, err := x.decode("xl/workbook.xml", &workbook)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, errors.New("workbook not found in xlsx")
+	}
+	rels := xlsxRelationships{}
+	if _, err := x.decode("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, nil, err
+	}
+	targets := map[string]string{}
+	for _, r := range rels.Relationships {
+		if strings.HasPrefix(r.Target, "/") {
+			targets[r.ID] = strings.TrimPrefix(r.Target, "/")
+		} else {
+			targets[r.ID] = path.Join("xl", r.Target)
+		}
+	}
+
+	names, paths := []string{}, []string{}
+	for _, s := range workbook.Sheets {
+		names = append(names, s.Name)
+		paths = append(paths, targets[s.RID])
+	}
+	return names, paths, nil
+}
+
+// XLSXSheetNames returns names of sheets in xlsx workbook.
+func XLSXSheetNames(r io.ReaderAt, size int64) ([]string, error) {
+	x, err := newXLSXReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	names, _, err := x.sheetPaths()
+	return names, err
+}
+
+// FromXLSX returns a table read from sheet of xlsx workbook. The first sheet is read when sheet is empty.
+// Numbers are read as float64 with number formats, as values read from spreadsheet.
+func FromXLSX(r io.ReaderAt, size int64, sheet string) (*Table, error) {
+	x, err := newXLSXReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	names, paths, err := x.sheetPaths()
+	if err != nil {
+		return nil, err
+	}
+	sheetPath := ""
+	for i, name := range names {
+		if name == sheet || (len(sheet) == 0 && i == 0) {
+			sheetPath = paths[i]
+			break
+		}
+	}
+	if len(sheetPath) == 0 {
+		return nil, sheetNotFoundError(sheet)
+	}
+
+	sharedStrings := xlsxSharedStrings{}
+	if _, err := x.decode("xl/sharedStrings.xml", &sharedStrings); err != nil {
+		return nil, err
+	}
+	styleSheet := xlsxStyleSheet{}
+	if _, err := x.decode("xl/styles.xml", &styleSheet); err != nil {
+		return nil, err
+	}
+	worksheet := xlsxWorksheet{}
+	ok, err := x.decode(sheetPath, &worksheet)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Errorf("%s not found in xlsx", sheetPath)
+	}
+
+	return tableFromXLSXWorksheet(worksheet, sharedStrings, styleSheet)
+}
+
+func tableFromXLSXWorksheet(worksheet xlsxWorksheet, sharedStrings xlsxSharedStrings, styleSheet xlsxStyleSheet) (*Table, error) {
+	numberFormats := map[int]string{}
+	for id, code := range xlsxBuiltInNumberFormats {
+		numberFormats[id] = code
+	}
+	for _, f := range styleSheet.NumFmts {
+		numberFormats[f.ID] = f.Code
+	}
+
+	t := NewGrowableTable(0, 0)
+	row := -1
+	for _, r := range worksheet.Rows {
+		if r.R > 0 {
+			row = r.R - 1
+		} else {
+			row++
+		}
+		if row >= maxSheetRows {
+			return nil, errors.Errorf("row %d is beyond the limit of %d rows", row+1, maxSheetRows)
+		}
+		col := -1
+		for _, c := range r.Cells {
+			if len(c.R) > 0 {
+				cellRow, cellCol, ok := parseCellRef(c.R)
+				if !ok || cellRow != row {
+					return nil, errors.Errorf("invalid cell reference %s in row %d", c.R, row+1)
+				}
+				col = cellCol
+			} else {
+				col++
+			}
+			if col >= maxSheetCols {
+				return nil, errors.Errorf("column %d in row %d is beyond the limit of %d columns", col+1, row+1, maxSheetCols)
+			}
+
+			var value interface{}
+			switch {
+			case c.T == "inlineStr":
+				value = c.Inline.String()
+			case c.V == nil:
+			case c.T == "s":
+				i, err := strconv.Atoi(*c.V)
+				if err != nil || i < 0 || i >= len(sharedStrings.Items) {
+					return nil, errors.Errorf("invalid shared string %s at %s", *c.V, c.R)
+				}
+				value = sharedStrings.Items[i].String()
+			case c.T == "str" || c.T == "e":
+				value = *c.V
+			case c.T == "b":
+				value = *c.V == "1"
+			default:
+				f, err := strconv.ParseFloat(*c.V, 64)
+				if err != nil {
+					return nil, errors.Errorf("invalid number %s at %s", *c.V, c.R)
+				}
+				value = f
+			}
+			if value != nil {
+				t.PutValue(row, col, value)
+			}
+
+			if c.S <= 0 || c.S >= len(styleSheet.CellXfs) {
+				continue
+			}
+			xf := styleSheet.CellXfs[c.S]
+			if code := numberFormats[xf.NumFmtID]; xf.NumFmtID > 0 && len(code) > 0 {
+				t.SetNumberFormatPattern(row, col, code)
+				t.SetNumberFormatType(row, col, numberFormatTypeOf(code))
+			}
+			if xf.FillID > 0 && xf.FillID < len(styleSheet.Fills) {
+				fill := styleSheet.Fills[xf.FillID].Pattern
+				if c, ok := fromARGB(fill.FgColor.RGB); ok && fill.Type == "solid" {
+					t.SetBackgroundColor(row, col, c)
+				}
+			}
+		}
+	}
+	t.SetGrowable(false)
+
+	for _, p := range worksheet.Panes {
+		if p.State == "frozen" {
+			t.FrozenRowCount = int64(p.YSplit)
+			t.FrozenColumnCount = int64(p.XSplit)
+		}
+	}
+	for _, c := range worksheet.Cols {
+		if !c.CustomWidth || c.Width <= 0 {
+			continue
+		}
+		// Widths are often declared for all columns of sheet.
+		for col := c.Min - 1; col < c.Max && col < t.cols; col++ {
+			t.SetColumnWidth(col, xlsxColumnPixels(c.Width))
+		}
+	}
+	return t, nil
+}
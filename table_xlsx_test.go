@@ -0,0 +1,172 @@
+package herschel
+
+import (
+	"archive/zip"
+	"bytes"
+	"image/color"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestXLSXRoundTrip(t *testing.T) {
+	table := NewTable(3, 3)
+	table.PutValuesAtRow(0, "name", "price", "date")
+	table.PutValuesAtRow(1, "a & b", 1234.5, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC))
+	table.PutValuesAtRow(2, true, 3, nil)
+	table.SetBackgroundColor(0, 0, color.RGBA{255, 0, 0, 255})
+	table.SetNumberFormatPattern(1, 1, "#,##0.00")
+	table.SetColumnWidth(0, 200)
+	table.FrozenRowCount = 1
+	table.FrozenColumnCount = 1
+
+	buf := bytes.NewBuffer(nil)
+	if err := table.ToXLSX(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := FromXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]interface{}{
+		{"name", "price", "date"},
+		{"a & b", 1234.5, 44563.0},
+		{true, 3.0, nil},
+	}
+	if !reflect.DeepEqual(read.Values(), expected) {
+		t.Errorf("Unexpected values: %v", read.Values())
+	}
+	if c := read.getBackgroundColor(0, 0); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("Unexpected background color: %v", c)
+	}
+	if p := read.getNumberFormatPattern(1, 1); p != "#,##0.00" {
+		t.Errorf("Unexpected number format: %s", p)
+	}
+	if p, ft := read.getNumberFormatPattern(1, 2), read.getNumberFormatType(1, 2); p != "yyyy-mm-dd" || ft != "DATE" {
+		t.Errorf("Unexpected date format: %s %s", p, ft)
+	}
+	if w := read.GetColumnWidth(0); w != 200 {
+		t.Errorf("Unexpected column width: %d", w)
+	}
+	if read.FrozenRowCount != 1 || read.FrozenColumnCount != 1 {
+		t.Errorf("Unexpected frozen rows / cols: %d, %d", read.FrozenRowCount, read.FrozenColumnCount)
+	}
+}
+
+func TestXLSXMultipleSheets(t *testing.T) {
+	a := NewTable(1, 1)
+	a.PutValue(0, 0, "a")
+	b := NewTable(1, 1)
+	b.PutValue(0, 0, "b")
+
+	buf := bytes.NewBuffer(nil)
+	if err := WriteXLSX(buf, XLSXSheet{Name: "First", Table: a}, XLSXSheet{Name: "Second", Table: b}); err != nil {
+		t.Fatal(err)
+	}
+	r := bytes.NewReader(buf.Bytes())
+
+	names, err := XLSXSheetNames(r, int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"First", "Second"}) {
+		t.Errorf("Unexpected sheet names: %v", names)
+	}
+
+	read, err := FromXLSX(r, int64(buf.Len()), "Second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.GetStringValue(0, 0) != "b" {
+		t.Errorf("Unexpected value: %v", read.GetValue(0, 0))
+	}
+
+	if _, err := FromXLSX(r, int64(buf.Len()), "Third"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("ErrSheetNotFound expected, got: %v", err)
+	}
+	if err := WriteXLSX(buf, XLSXSheet{Name: "A", Table: a}, XLSXSheet{Name: "a", Table: b}); err == nil {
+		t.Error("Duplicated sheet names should fail.")
+	}
+	for _, name := range []string{"a/b", "[a]", "a:b", "a*", "a?", `a\b`, "abcdefghijklmnopqrstuvwxyz123456"} {
+		if err := WriteXLSX(buf, XLSXSheet{Name: name, Table: a}); err == nil {
+			t.Errorf("Invalid sheet name %s should fail.", name)
+		}
+	}
+	if err := WriteXLSX(bytes.NewBuffer(nil), XLSXSheet{Name: "abcdefghijklmnopqrstuvwxyz12345", Table: a}); err != nil {
+		t.Errorf("Sheet name of 31 characters should be written: %v", err)
+	}
+}
+
+func TestXLSXSharedStrings(t *testing.T) {
+	// Workbook as written by spreadsheet applications, with shared strings and cells without references.
+	files := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Data" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="worksheet" Target="/xl/worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>plain</t></si><si><r><t>rich </t></r><r><t>text</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<cols><col min="1" max="16384" width="10" customWidth="1"/></cols>
+<sheetData><row><c t="s"><v>0</v></c><c t="s"><v>1</v></c></row><row r="3"><c r="B3" t="str"><v>formula</v></c></row></sheetData></worksheet>`,
+	}
+	buf := zipFiles(t, files)
+	read, err := FromXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "Data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"plain", "rich text"}, {nil, nil}, {nil, "formula"}}
+	if !reflect.DeepEqual(read.Values(), expected) {
+		t.Errorf("Unexpected values: %v", read.Values())
+	}
+	if read.GetColumnWidth(1) != 75 || read.GetColumnWidth(2) != 0 {
+		t.Errorf("Widths should be set to columns of table: %d, %d", read.GetColumnWidth(1), read.GetColumnWidth(2))
+	}
+}
+
+func TestXLSXCellLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		rows string
+	}{
+		{"HugeRowOfCell", `<row><c r="A2000000000"><v>1</v></c></row>`},
+		{"LongColumnName", `<row r="1"><c r="AAAAAAAAAAAAAAAAAAAAAAAAAAAAAA1"><v>1</v></c></row>`},
+		{"HugeRow", `<row r="2000000000"><c><v>1</v></c></row>`},
+		{"ColumnBeyondLimit", `<row r="1"><c r="XFD1"><v>1</v></c><c><v>2</v></c></row>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := zipFiles(t, map[string]string{
+				"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Data" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+				"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="worksheet" Target="/xl/worksheets/data.xml"/></Relationships>`,
+				"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + tt.rows + `</sheetData></worksheet>`,
+			})
+			if _, err := FromXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "Data"); err == nil {
+				t.Error("Cells beyond the limits of worksheet should fail.")
+			}
+		})
+	}
+}
+
+// zipFiles returns a zip archive of files.
+func zipFiles(t *testing.T, files map[string]string) *bytes.Buffer {
+	buf := bytes.NewBuffer(nil)
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}